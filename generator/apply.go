@@ -0,0 +1,286 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply [spec.yaml]",
+	Short: "Generate every resource listed in a spec file",
+	Long: `Generate the resources listed in a spec file in one run, e.g. to bootstrap a
+service:
+
+  template-set: default      # optional, like --template-set
+  resources:
+    - kind: crud
+      name: order
+      fields: [amount:float, status:string]
+    - kind: suite
+      name: admin/user
+      options:
+        kind: api            # any flag of the command, without the dashes
+        with-test: true
+    - kind: controller
+      name: auth
+      options:
+        no-route: true
+        var: {author: platform-team}
+
+The kind is any generator subcommand (or alias), including custom kinds of the
+template set. Flags given to apply (--force, --dry-run, --with-test, ...) apply
+to every resource that does not set them. The kinds, names, fields and options
+of the whole spec are checked before anything is generated. A resource that
+fails is rolled back and the remaining resources are still generated; the
+summary counts the files created, skipped, overwritten and the resources that
+failed, and apply exits non-zero if any resource failed. Hooks run once, after
+all resources succeeded.`,
+	DisableFlagsInUseLine: true,
+	Args:                  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := loadApplySpec(args[0])
+		if err != nil {
+			return err
+		}
+		if spec.TemplateSet != "" && !cmd.Flags().Changed("template-set") {
+			cmd.Flags().Set("template-set", spec.TemplateSet)
+		}
+
+		// 先检查所有资源，避免生成一部分后才发现清单错误
+		commands := make([]*cobra.Command, len(spec.Resources))
+		var errs []error
+		for i, resource := range spec.Resources {
+			if commands[i], err = resource.command(cmd); err != nil {
+				errs = append(errs, fmt.Errorf("resources[%d] (%s): %w", i, resource, err))
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("invalid spec %s:\n%w", args[0], errors.Join(errs...))
+		}
+
+		failed := 0
+		for i, resource := range spec.Resources {
+			cmd.Printf("==> %s\n", resource)
+			if err := applyResource(cmd, commands[i], resource); err != nil {
+				cmd.Printf("Failed %s: %v\n", resource, err)
+				failed++
+			}
+		}
+
+		verb := "Applied"
+		if isDryRun(cmd) {
+			verb = "Would apply"
+		}
+		actions := currentSession.actions
+		summary := []string{
+			fmt.Sprintf("%d created", actions[actionCreated]),
+			fmt.Sprintf("%d skipped", actions[actionSkipped]),
+			fmt.Sprintf("%d overwritten", actions[actionOverwritten]),
+		}
+		if actions[actionMerged] > 0 {
+			summary = append(summary, fmt.Sprintf("%d merged", actions[actionMerged]))
+		}
+		summary = append(summary, fmt.Sprintf("%d failed", failed))
+		cmd.Printf("%s %d resource(s) from %s: %s\n", verb, len(spec.Resources), args[0], strings.Join(summary, ", "))
+		if failed > 0 {
+			return fmt.Errorf("%d of %d resource(s) failed", failed, len(spec.Resources))
+		}
+		return nil
+	},
+}
+
+// ApplySpec 是 gouno gen apply 读取的资源清单
+type ApplySpec struct {
+	TemplateSet string          `yaml:"template-set"` // 所有资源默认使用的模板集，--template-set 优先
+	Resources   []ApplyResource `yaml:"resources"`
+}
+
+// ApplyResource 是清单中的一个资源，相当于执行一次 gouno gen <kind> <name> <fields...> [options]
+type ApplyResource struct {
+	Kind   string   `yaml:"kind"`   // 生成命令的名称或别名，如 crud、suite、controller 或模板集的自定义类型
+	Name   string   `yaml:"name"`   // 资源名称，from-sql / from-openapi 为文件路径
+	Fields []string `yaml:"fields"` // 字段定义 name:type[:option...]
+
+	// Options 是命令的标志（不含 --），列表表示重复的标志，映射表示 key=value 形式的重复标志（如 var）
+	Options map[string]any `yaml:"options"`
+}
+
+func (r ApplyResource) String() string {
+	return strings.TrimSpace(r.Kind + " " + r.Name)
+}
+
+// applyExcluded 是不能在清单中使用的子命令，它们不生成资源
+var applyExcluded = []string{"apply", "destroy", "status", "upgrade", "template", "help", "completion"}
+
+// loadApplySpec 读取并解析资源清单，未知的字段视为错误
+func loadApplySpec(path string) (*ApplySpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	var spec ApplySpec
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+	if len(spec.Resources) == 0 {
+		return nil, fmt.Errorf("no resources listed in %s", path)
+	}
+	return &spec, nil
+}
+
+// command 返回执行资源的子命令，并检查名称、参数与选项
+func (r ApplyResource) command(apply *cobra.Command) (*cobra.Command, error) {
+	if r.Kind == "" {
+		return nil, fmt.Errorf("kind is required")
+	}
+	if r.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	var available []string
+	var sub *cobra.Command
+	for _, c := range GeneratorCmd.Commands() {
+		if slices.Contains(applyExcluded, c.Name()) {
+			continue
+		}
+		available = append(available, c.Name())
+		if c.Name() == r.Kind || c.HasAlias(r.Kind) {
+			sub = c
+		}
+	}
+	if sub == nil {
+		slices.Sort(available)
+		return nil, fmt.Errorf("unknown kind %q (available: %s)", r.Kind, strings.Join(available, ", "))
+	}
+	if err := sub.ValidateArgs(r.args()); err != nil {
+		return nil, err
+	}
+	// 名称与字段定义在执行时才会检查，这里提前检查，避免靠后的资源在前面的资源写入后才失败；
+	// from-sql 等命令的参数是文件路径，不检查名称
+	if usage := strings.Fields(sub.Use); len(usage) > 1 && usage[1] == "[name]" {
+		if err := validateName(r.Name, sub.Name()); err != nil {
+			return nil, err
+		}
+	}
+	args := r.args()
+	if sub == crudCmd {
+		var err error
+		if args, err = withIDField(args); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := parseFields(args[1:]); err != nil {
+		return nil, err
+	}
+	// 设置一次选项以检查名称与取值，执行时重新设置
+	defer resetFlags(sub)
+	return sub, r.setFlags(apply, sub)
+}
+
+func (r ApplyResource) args() []string {
+	return append([]string{r.Name}, r.Fields...)
+}
+
+// setFlags 将资源的选项设置为子命令的标志，资源未设置的标志使用 apply 命令行中指定的值
+func (r ApplyResource) setFlags(apply, sub *cobra.Command) error {
+	for _, name := range slices.Sorted(maps.Keys(r.Options)) {
+		if sub.Flags().Lookup(name) == nil {
+			return fmt.Errorf("unknown option %q for %s", name, sub.Name())
+		}
+		for _, value := range optionValues(r.Options[name]) {
+			if err := sub.Flags().Set(name, value); err != nil {
+				return fmt.Errorf("option %s: %w", name, err)
+			}
+		}
+	}
+	var err error
+	apply.Flags().Visit(func(f *pflag.Flag) {
+		if _, ok := r.Options[f.Name]; ok || sub.Flags().Lookup(f.Name) == nil || err != nil {
+			return
+		}
+		values := []string{f.Value.String()}
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			values = v.GetSlice()
+		}
+		for _, value := range values {
+			if err = sub.Flags().Set(f.Name, value); err != nil {
+				err = fmt.Errorf("--%s: %w", f.Name, err)
+				return
+			}
+		}
+	})
+	return err
+}
+
+// optionValues 将选项的取值转为标志的字符串形式：列表为重复的标志，映射按键排序转为 key=value
+func optionValues(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		var values []string
+		for _, item := range v {
+			values = append(values, optionValues(item)...)
+		}
+		return values
+	case map[string]any:
+		var values []string
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			values = append(values, key+"="+fmt.Sprint(v[key]))
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// applyResource 在事务中执行一个资源，失败时回滚其写入的文件并撤销其文件计数
+func applyResource(apply, sub *cobra.Command, resource ApplyResource) error {
+	defer resetFlags(sub)
+	if err := resource.setFlags(apply, sub); err != nil {
+		return err
+	}
+	// 模板变量按模板集缓存，资源自行指定变量时不与其他资源共用
+	if _, ok := resource.Options["var"]; ok {
+		variables := currentSession.variables
+		currentSession.variables = make(map[string]map[string]string)
+		defer func() { currentSession.variables = variables }()
+	}
+	actions := maps.Clone(currentSession.actions)
+	err := inTransaction(sub, func() error {
+		return sub.RunE(sub, resource.args())
+	})
+	if err != nil {
+		currentSession.actions = actions
+	}
+	return err
+}
+
+// resetFlags 将命令的标志恢复为默认值，使 apply 依次执行的资源互不影响
+func resetFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			v.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+func init() {
+	applyCmd.Flags().BoolP("force", "f", false, "force overwrite")
+	applyCmd.Flags().String("template-set", "", "template set for resources that do not set one (default from the spec)")
+	addGenerateFlags(applyCmd)
+}
@@ -13,7 +13,11 @@ var controllerCmd = &cobra.Command{
 	Args:                  cobra.ExactArgs(1),
 	DisableFlagsInUseLine: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return generateFile(cmd, args, "controller", defaultControllerPath)
+		controller, err := generateTemplateFile(cmd, args, "controller", "controller", defaultControllerPath)
+		if err != nil {
+			return err
+		}
+		return registerRoute(cmd, newRouteRegistration(controller))
 	},
 }
 
@@ -23,4 +27,6 @@ func init() {
 	controllerCmd.Flags().StringP("path", "p", defaultControllerPath, "path to controller")
 	controllerCmd.Flags().BoolP("force", "f", false, "force overwrite")
 	controllerCmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(controllerCmd)
+	controllerCmd.Flags().Bool("no-route", false, "skip route registration in the configured router file")
 }
@@ -0,0 +1,329 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var crudCmd = &cobra.Command{
+	Use:                   "crud [name] [field:type[:option]...]",
+	Short:                 "Generate CRUD scaffold (domain, repository, service, controller)",
+	DisableFlagsInUseLine: true,
+	Args:                  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectRoot, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		if readModulePath(projectRoot) == "" {
+			return fmt.Errorf("crud requires a go.mod in %s to resolve import paths", projectRoot)
+		}
+
+		if args, err = withIDField(args); err != nil {
+			return err
+		}
+		// 任一层失败时还原已生成的文件，避免留下不完整的资源
+		return inTransaction(cmd, func() error {
+			if err := generateFile(cmd, args, "domain", defaultDomainPath); err != nil {
+				return err
+			}
+			repository, err := generateTemplateFile(cmd, args, "repository", "crud_repository", defaultRepositoryPath)
+			if err != nil {
+				return err
+			}
+			service, err := generateTemplateFile(cmd, args, "service", "crud_service", defaultServicePath)
+			if err != nil {
+				return err
+			}
+			controller, err := generateTemplateFile(cmd, args, "controller", "crud_controller", defaultControllerPath)
+			if err != nil {
+				return err
+			}
+
+			structName := controller.Data.StructName
+			reg := &routeRegistration{Controller: controller}
+			reg.Constructor = fmt.Sprintf("%s.New%sController(%s.New%sService(%s.NewMemory%sRepository()))",
+				reg.importFile(controller), structName, reg.importFile(service), structName, reg.importFile(repository), structName)
+			return registerRoute(cmd, reg)
+		})
+	},
+}
+
+func init() {
+	crudCmd.Flags().BoolP("force", "f", false, "force overwrite")
+	crudCmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(crudCmd)
+	crudCmd.Flags().Bool("no-route", false, "skip route registration in the configured router file")
+}
+
+// withIDField 在字段定义中未包含 id 时补充 id:int64 作为主键
+// 模板只能从路由参数解析字符串与整数类型的主键，其他类型（如 float、time 或可为空的 id）返回错误
+func withIDField(args []string) ([]string, error) {
+	for _, spec := range args[1:] {
+		if name, _, _ := strings.Cut(spec, ":"); !strings.EqualFold(name, "id") {
+			continue
+		}
+		id, err := parseField(spec)
+		if err != nil {
+			return nil, err
+		}
+		if id.Type != "string" && !id.IsInteger() {
+			return nil, fmt.Errorf("unsupported id type %q in %q: crud ids must be string, uuid or an integer type (int, int8...int64, uint, uint8...uint64) and cannot be null", id.Type, spec)
+		}
+		return args, nil
+	}
+	return append([]string{args[0], "id:int64"}, args[1:]...), nil
+}
+
+const crudRepositoryTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	{{.Import "domain"}}
+)
+
+// Err{{.StructName}}NotFound is returned when the requested {{.Snake}} does not exist.
+var Err{{.StructName}}NotFound = errors.New("{{.Snake}} not found")
+
+type {{.StructName}}Repository interface {
+	List(ctx context.Context) ([]*domain.{{.StructName}}, error)
+	Get(ctx context.Context, id {{.ID.Type}}) (*domain.{{.StructName}}, error)
+	Create(ctx context.Context, {{lowerFirst .StructName}} *domain.{{.StructName}}) error
+	Update(ctx context.Context, {{lowerFirst .StructName}} *domain.{{.StructName}}) error
+	Delete(ctx context.Context, id {{.ID.Type}}) error
+}
+
+type Memory{{.StructName}}Repository struct {
+	mu    sync.RWMutex
+	{{- if .ID.IsInteger}}
+	seq   {{.ID.Type}}
+	{{- end}}
+	items map[{{.ID.Type}}]*domain.{{.StructName}}
+}
+
+func NewMemory{{.StructName}}Repository() *Memory{{.StructName}}Repository {
+	return &Memory{{.StructName}}Repository{
+		items: make(map[{{.ID.Type}}]*domain.{{.StructName}}),
+	}
+}
+
+func (r *Memory{{.StructName}}Repository) List(ctx context.Context) ([]*domain.{{.StructName}}, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := make([]*domain.{{.StructName}}, 0, len(r.items))
+	for _, item := range r.items {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (r *Memory{{.StructName}}Repository) Get(ctx context.Context, id {{.ID.Type}}) (*domain.{{.StructName}}, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, Err{{.StructName}}NotFound
+	}
+	return item, nil
+}
+
+func (r *Memory{{.StructName}}Repository) Create(ctx context.Context, {{lowerFirst .StructName}} *domain.{{.StructName}}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	{{- if .ID.IsInteger}}
+
+	r.seq++
+	{{lowerFirst .StructName}}.ID = r.seq
+	{{- end}}
+
+	r.items[{{lowerFirst .StructName}}.ID] = {{lowerFirst .StructName}}
+	return nil
+}
+
+func (r *Memory{{.StructName}}Repository) Update(ctx context.Context, {{lowerFirst .StructName}} *domain.{{.StructName}}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[{{lowerFirst .StructName}}.ID]; !ok {
+		return Err{{.StructName}}NotFound
+	}
+	r.items[{{lowerFirst .StructName}}.ID] = {{lowerFirst .StructName}}
+	return nil
+}
+
+func (r *Memory{{.StructName}}Repository) Delete(ctx context.Context, id {{.ID.Type}}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[id]; !ok {
+		return Err{{.StructName}}NotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+`
+
+const crudServiceTemplate = `package {{.Package}}
+
+import (
+	"context"
+
+	{{.Import "domain"}}
+	{{.Import "repository"}}
+)
+
+// Err{{.StructName}}NotFound is returned when the requested {{.Snake}} does not exist.
+var Err{{.StructName}}NotFound = repository.Err{{.StructName}}NotFound
+
+type {{.StructName}}Service struct {
+	repository repository.{{.StructName}}Repository
+}
+
+func New{{.StructName}}Service(repository repository.{{.StructName}}Repository) *{{.StructName}}Service {
+	return &{{.StructName}}Service{repository: repository}
+}
+
+func (s *{{.StructName}}Service) List(ctx context.Context) ([]*domain.{{.StructName}}, error) {
+	return s.repository.List(ctx)
+}
+
+func (s *{{.StructName}}Service) Get(ctx context.Context, id {{.ID.Type}}) (*domain.{{.StructName}}, error) {
+	return s.repository.Get(ctx, id)
+}
+
+func (s *{{.StructName}}Service) Create(ctx context.Context, {{lowerFirst .StructName}} *domain.{{.StructName}}) error {
+	return s.repository.Create(ctx, {{lowerFirst .StructName}})
+}
+
+func (s *{{.StructName}}Service) Update(ctx context.Context, {{lowerFirst .StructName}} *domain.{{.StructName}}) error {
+	return s.repository.Update(ctx, {{lowerFirst .StructName}})
+}
+
+func (s *{{.StructName}}Service) Delete(ctx context.Context, id {{.ID.Type}}) error {
+	return s.repository.Delete(ctx, id)
+}
+`
+
+const crudControllerTemplate = `package {{.Package}}
+
+import (
+	"errors"
+	"net/http"
+	{{- if .ID.IsInteger}}
+	"strconv"
+	{{- end}}
+
+	"github.com/gin-gonic/gin"
+	"github.com/rushairer/gouno"
+
+	{{.Import "domain"}}
+	{{.Import "service"}}
+)
+
+type {{.StructName}}Controller struct {
+	service *service.{{.StructName}}Service
+}
+
+func New{{.StructName}}Controller(service *service.{{.StructName}}Service) *{{.StructName}}Controller {
+	return &{{.StructName}}Controller{service: service}
+}
+
+func (c *{{.StructName}}Controller) List(ctx *gin.Context) {
+	items, err := c.service.List(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gouno.NewInternalServerErrorResponse())
+		return
+	}
+	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse(items))
+}
+
+func (c *{{.StructName}}Controller) Get(ctx *gin.Context) {
+	id, ok := c.id(ctx)
+	if !ok {
+		return
+	}
+	item, err := c.service.Get(ctx, id)
+	if err != nil {
+		c.respondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse(item))
+}
+
+func (c *{{.StructName}}Controller) Create(ctx *gin.Context) {
+	var item domain.{{.StructName}}
+	if err := ctx.ShouldBindJSON(&item); err != nil {
+		ctx.JSON(http.StatusBadRequest, gouno.NewBadRequestResponse())
+		return
+	}
+	if err := c.service.Create(ctx, &item); err != nil {
+		c.respondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse(item))
+}
+
+func (c *{{.StructName}}Controller) Update(ctx *gin.Context) {
+	id, ok := c.id(ctx)
+	if !ok {
+		return
+	}
+	var item domain.{{.StructName}}
+	if err := ctx.ShouldBindJSON(&item); err != nil {
+		ctx.JSON(http.StatusBadRequest, gouno.NewBadRequestResponse())
+		return
+	}
+	item.ID = id
+	if err := c.service.Update(ctx, &item); err != nil {
+		c.respondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse(item))
+}
+
+func (c *{{.StructName}}Controller) Delete(ctx *gin.Context) {
+	id, ok := c.id(ctx)
+	if !ok {
+		return
+	}
+	if err := c.service.Delete(ctx, id); err != nil {
+		c.respondError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse(nil))
+}
+
+func (c *{{.StructName}}Controller) id(ctx *gin.Context) ({{.ID.Type}}, bool) {
+	{{- if .ID.IsInteger}}
+	id, err := strconv.Parse{{if .ID.IsUnsigned}}Uint{{else}}Int{{end}}(ctx.Param("id"), 10, {{.ID.BitSize}})
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gouno.NewBadRequestResponse())
+		return 0, false
+	}
+	return {{.ID.Type}}(id), true
+	{{- else}}
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gouno.NewBadRequestResponse())
+		return id, false
+	}
+	return id, true
+	{{- end}}
+}
+
+func (c *{{.StructName}}Controller) respondError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.Err{{.StructName}}NotFound) {
+		ctx.JSON(http.StatusNotFound, gouno.NewNotFoundResponse())
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gouno.NewInternalServerErrorResponse())
+}
+`
@@ -0,0 +1,277 @@
+package generator
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var destroyCmd = &cobra.Command{
+	Use:   "destroy [type] [name]",
+	Short: "Remove generated files that have not been modified",
+	Long: `Remove the files a previous generation created for a resource, e.g.
+gouno gen destroy suite user.
+
+A file is only removed while its content still matches the output recorded in
+.gouno/cache when it was generated; modified files are kept unless --force is
+given, and files with no recorded output are always kept. For suites and crud,
+a layer whose package is still imported by a kept file of a later layer (e.g. a
+modified repository importing the domain) is kept as well. Destroying a controller also removes its registration from the router
+file configured in .gouno.yaml. If any step fails, the removed files and the
+router file are restored. The type is one of domain, repository,
+service, controller, task, suite (with --kind), crud or a custom kind of the
+template set; --path must match the one used for generation.`,
+	DisableFlagsInUseLine: true,
+	Args:                  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		typeName, name := args[0], args[1]
+		targets, err := destroyTargets(cmd, typeName)
+		if err != nil {
+			return err
+		}
+		defer clear(currentSession.paths)
+
+		found := false
+		// 按与生成相反的顺序删除，任一类型失败时还原已删除的文件与路由注册
+		err = inTransaction(cmd, func() error {
+			var kept []string
+			for _, target := range slices.Backward(targets) {
+				ok, err := destroyType(cmd, name, target, &kept)
+				if err != nil {
+					return fmt.Errorf("%s: %w", target.typeName, err)
+				}
+				found = found || ok
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if !found {
+			cmd.Printf("No generated files found for %s %s\n", typeName, name)
+		}
+		return nil
+	},
+}
+
+// destroyTarget 是 destroy 需要删除的一种生成类型
+type destroyTarget struct {
+	typeName     string
+	templateName string
+	defaultPath  string
+}
+
+// destroyTargets 返回 destroy 的类型参数对应的生成类型，套件会在 currentSession.paths 中设置成员目录
+func destroyTargets(cmd *cobra.Command, typeName string) ([]destroyTarget, error) {
+	switch typeName {
+	case "suite":
+		_, members, err := resolveSuite(cmd)
+		if err != nil {
+			return nil, err
+		}
+		var targets []destroyTarget
+		for _, member := range members {
+			targets = append(targets, destroyTarget{member.Type, member.Type, currentSession.paths[member.Type]})
+		}
+		return targets, nil
+	case "crud":
+		return []destroyTarget{
+			{"domain", "domain", defaultDomainPath},
+			{"repository", "crud_repository", defaultRepositoryPath},
+			{"service", "crud_service", defaultServicePath},
+			{"controller", "crud_controller", defaultControllerPath},
+		}, nil
+	}
+	if path, ok := builtinTypePaths[typeName]; ok {
+		return []destroyTarget{{typeName, typeName, path}}, nil
+	}
+	manifest, err := loadManifest(resolveTemplateSet(cmd))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := manifest.kind(typeName); ok {
+		return []destroyTarget{{typeName, typeName, defaultKindPath(typeName)}}, nil
+	}
+	available := append(suiteTypes(manifest), "crud", "suite")
+	slices.Sort(available)
+	return nil, fmt.Errorf("unknown type %q (available: %s)", typeName, strings.Join(available, ", "))
+}
+
+// destroyType 删除一种类型为 name 生成的文件（包括目录模板的各个文件、测试文件与 mock），
+// 控制器被删除（或已不存在）时同时移除其路由注册；返回是否找到了任何文件。
+// kept 是此前保留的文件，其中有文件导入本类型的包时保留本类型的文件，以免项目无法编译；本类型保留的文件会追加到 kept
+func destroyType(cmd *cobra.Command, name string, target destroyTarget, kept *[]string) (bool, error) {
+	templateSet := resolveTemplateSet(cmd)
+	templates, err := loadTemplateFiles(cmd, templateSet, target.templateName)
+	if err != nil {
+		return false, err
+	}
+	data, filePath, err := newTemplateData(cmd, []string{name}, target.typeName, target.defaultPath)
+	if err != nil {
+		return false, err
+	}
+	paths := []string{filePath, strings.TrimSuffix(filePath, ".go") + "_test.go"}
+	if slices.ContainsFunc(templates, func(tmpl templateFile) bool { return tmpl.Name != "" }) {
+		// 目录模板的文件名可能引用模板变量
+		manifest, err := loadManifest(templateSet)
+		if err != nil {
+			return false, err
+		}
+		if data.Vars, err = templateVariables(cmd, templateSet, manifest); err != nil {
+			return false, err
+		}
+		rendered, err := templateFilePaths(target.templateName, templates, filePath, data, true)
+		if err != nil {
+			return false, err
+		}
+		paths = slices.Sorted(maps.Values(rendered))
+	}
+
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	if importPath := dirImportPath(data.Module, projectRoot, filepath.Dir(filePath)); importPath != "" {
+		if importer := importingFile(*kept, importPath); importer != "" {
+			found := false
+			for _, path := range paths {
+				if _, err := os.Stat(path); err == nil {
+					cmd.Printf("Kept %s file: %s (imported by kept file %s)\n", target.typeName, path, importer)
+					*kept = append(*kept, path)
+					found = true
+				}
+			}
+			return found, nil
+		}
+	}
+
+	found := false
+	controllerKept := false
+	for _, path := range paths {
+		action, err := destroyFile(cmd, target.typeName, path)
+		if err != nil {
+			return found, err
+		}
+		found = found || action != actionMissing
+		controllerKept = controllerKept || (action == actionKept && !strings.HasSuffix(path, "_test.go"))
+		if action == actionKept {
+			*kept = append(*kept, path)
+		}
+	}
+	if _, ok := mockSources[target.typeName]; ok {
+		// gouno gen repository --mock / gouno gen mock 生成的 fake
+		action, err := destroyFile(cmd, "mock", filepath.Join(filepath.Dir(filePath), mockDirName, filepath.Base(filePath)))
+		if err != nil {
+			return found, err
+		}
+		found = found || action != actionMissing
+	}
+	if target.typeName == "controller" && !controllerKept {
+		if err := unregisterRoute(cmd, data, dirImportPath(data.Module, projectRoot, filepath.Dir(filePath))); err != nil {
+			return found, err
+		}
+	}
+	return found, nil
+}
+
+// destroy 对单个文件执行的操作
+const (
+	actionRemoved = "removed"
+	actionKept    = "kept"
+	actionMissing = "missing"
+)
+
+// destroyFile 在文件内容与 .gouno/cache 中记录的生成结果一致（或指定 --force）时删除文件及其记录，
+// 并删除因此变空的目录；没有记录的文件不是 gouno 生成的，即使指定 --force 也会保留。--dry-run 时只报告
+func destroyFile(cmd *cobra.Command, typeName, path string) (string, error) {
+	current, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		// 已被手动删除的文件不再保留记录
+		if !isDryRun(cmd) {
+			return actionMissing, forgetLock(path)
+		}
+		return actionMissing, nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read %s file: %w", typeName, err)
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	pristine, recorded := loadPristine(path)
+	switch {
+	case !recorded:
+		cmd.Printf("Kept %s file with no recorded generated output: %s\n", typeName, path)
+		return actionKept, nil
+	case !force && contentHash(current) != contentHash([]byte(pristine)):
+		cmd.Printf("Kept modified %s file: %s (use --force to remove)\n", typeName, path)
+		return actionKept, nil
+	}
+
+	if isDryRun(cmd) {
+		cmd.Printf("Would remove %s file: %s\n", typeName, path)
+		return actionRemoved, nil
+	}
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	currentSession.tx.recordFile(path)
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove %s file: %w", typeName, err)
+	}
+	if err := forgetLock(path); err != nil {
+		return "", err
+	}
+	removeEmptyDirs(filepath.Dir(path), projectRoot)
+	if cachePath, _ := pristinePath(path); cachePath != "" && recorded {
+		currentSession.tx.recordFile(cachePath)
+		if err := os.Remove(cachePath); err != nil {
+			return "", fmt.Errorf("failed to remove generated output record: %w", err)
+		}
+		removeEmptyDirs(filepath.Dir(cachePath), filepath.Join(projectRoot, templateDirName, cacheDirName))
+	}
+	cmd.Printf("Removed %s file: %s\n", typeName, path)
+	return actionRemoved, nil
+}
+
+// importingFile 返回 files 中导入了 importPath 的第一个文件，没有时返回空字符串
+func importingFile(files []string, importPath string) string {
+	fset := token.NewFileSet()
+	for _, path := range files {
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			if p, _ := strconv.Unquote(spec.Path.Value); p == importPath {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// removeEmptyDirs 自 dir 向上删除空目录，直到 root（不含）或遇到非空目录
+func removeEmptyDirs(dir, root string) {
+	for ; dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
+func init() {
+	destroyCmd.Flags().StringP("kind", "k", "", "suite to destroy, as declared under suites in .gouno.yaml (default \"default\")")
+	destroyCmd.Flags().StringP("path", "p", "", "path the files were generated in (base directory for suites)")
+	destroyCmd.Flags().BoolP("force", "f", false, "also remove files that were modified after generation")
+	destroyCmd.Flags().String("template-set", "", "template set name")
+	destroyCmd.Flags().Bool("dry-run", false, "report the files that would be removed without removing them")
+	destroyCmd.Flags().Bool("no-route", false, "keep the controller's registration in the router file")
+}
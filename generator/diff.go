@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext 是统一 diff 输出中每个变更块前后保留的上下文行数
+const diffContext = 3
+
+// diffOp 是行级差异中的一个操作
+type diffOp struct {
+	Kind byte   // ' ' 表示相同，'-' 表示删除，'+' 表示新增
+	Line string // 含换行符的行内容，文件最后一行可能不含换行符
+}
+
+// splitLines 将文本按行拆分，每行保留末尾的换行符
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines 基于最长公共子序列计算 a 到 b 的行级差异
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] 表示 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// unifiedDiff 返回 oldText 到 newText 的统一 diff，内容相同时返回空字符串
+// oldName 为空表示新建文件，旧文件名显示为 /dev/null
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	if oldName == "" {
+		oldName = "/dev/null"
+	}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	// oldLine/newLine 记录每个操作之前旧/新文件中已处理的行数
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+	for k, op := range ops {
+		oldLine[k+1], newLine[k+1] = oldLine[k], newLine[k]
+		if op.Kind != '+' {
+			oldLine[k+1]++
+		}
+		if op.Kind != '-' {
+			newLine[k+1]++
+		}
+	}
+
+	for start := 0; start < len(ops); {
+		// 找到下一个变更，并向后扩展直到连续相同的行超过两倍上下文
+		for start < len(ops) && ops[start].Kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		end := start
+		for k := start; k < len(ops); k++ {
+			if ops[k].Kind != ' ' {
+				end = k + 1
+			} else if k-end >= 2*diffContext {
+				break
+			}
+		}
+		from := max(start-diffContext, 0)
+		to := min(end+diffContext, len(ops))
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(oldLine[from], oldLine[to]-oldLine[from]),
+			hunkRange(newLine[from], newLine[to]-newLine[from]))
+		for _, op := range ops[from:to] {
+			sb.WriteByte(op.Kind)
+			sb.WriteString(op.Line)
+			if !strings.HasSuffix(op.Line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = to
+	}
+	return sb.String()
+}
+
+// hunkRange 按统一 diff 的格式输出起始行与行数
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,31 @@
+package generator
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("identical", func(t *testing.T) {
+		if d := unifiedDiff("a", "b", "x\n", "x\n"); d != "" {
+			t.Errorf("unifiedDiff = %q; want empty", d)
+		}
+	})
+
+	t.Run("new file", func(t *testing.T) {
+		got := unifiedDiff("", "foo.go", "", "a\nb")
+		want := "--- /dev/null\n+++ foo.go\n@@ -0,0 +1,2 @@\n+a\n+b\n\\ No newline at end of file\n"
+		if got != want {
+			t.Errorf("unifiedDiff =\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("hunks", func(t *testing.T) {
+		old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+		new := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16\n"
+		got := unifiedDiff("a", "b", old, new)
+		want := "--- a\n+++ b\n" +
+			"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+			"@@ -11,5 +11,5 @@\n 11\n 12\n 13\n-14\n 15\n+16\n"
+		if got != want {
+			t.Errorf("unifiedDiff =\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
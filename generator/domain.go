@@ -7,10 +7,10 @@ import (
 )
 
 var domainCmd = &cobra.Command{
-	Use:                   "domain [name]",
+	Use:                   "domain [name] [field:type[:option]...]",
 	Short:                 "Generate domain",
 	Aliases:               []string{"d"},
-	Args:                  cobra.ExactArgs(1),
+	Args:                  cobra.MinimumNArgs(1),
 	DisableFlagsInUseLine: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return generateFile(cmd, args, "domain", defaultDomainPath)
@@ -23,4 +23,5 @@ func init() {
 	domainCmd.Flags().StringP("path", "p", defaultDomainPath, "path to domain")
 	domainCmd.Flags().BoolP("force", "f", false, "force overwrite")
 	domainCmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(domainCmd)
 }
@@ -0,0 +1,4 @@
+package generator
+
+// RegisterKindCommands 让外部测试在切换工作目录与 HOME 后重新注册自定义类型子命令
+var RegisterKindCommands = registerKindCommands
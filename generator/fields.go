@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rushairer/gouno/utility"
+)
+
+// field 是命令行字段定义 name:type[:option...] 解析后的结果
+// 例如 email:string:unique
+type field struct {
+	Name    string   // Go 字段名，如 Email
+	Snake   string   // JSON/数据库列名，如 email
+	Type    string   // Go 类型，如 string
+	Options []string // 附加选项，如 unique
+}
+
+// fieldTypes 是字段定义中可用的类型简写，未列出的类型按 Go 类型原样使用
+var fieldTypes = map[string]string{
+	"text":     "string",
+	"uuid":     "string",
+	"float":    "float64",
+	"decimal":  "float64",
+	"time":     "time.Time",
+	"date":     "time.Time",
+	"datetime": "time.Time",
+	"bytes":    "[]byte",
+	"json":     "json.RawMessage",
+}
+
+// typeImports 是字段类型中的包限定符与其导入路径的对应关系
+var typeImports = map[string]string{
+	"time": "time",
+	"json": "encoding/json",
+	"sql":  "database/sql",
+}
+
+// typeQualifierPattern 匹配字段类型中的包限定符，如 *sql.NullString、[]time.Time 与 map[string]json.RawMessage 中的 sql、time 与 json；
+// 限定符必须是完整的标识符，mysql.NullTime 不会匹配 sql
+var typeQualifierPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.])([A-Za-z_][A-Za-z0-9_]*)\.`)
+
+// fieldOptions 是字段定义中支持的选项
+//
+//	pk     主键，写入 db tag
+//	unique 唯一约束，写入 db tag
+//	index  普通索引，写入 db tag
+//	null   可为空，字段类型变为指针
+var fieldOptions = []string{"pk", "unique", "index", "null"}
+
+// initialisms 是字段名中需要保持全大写的常见缩写
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ip":   "IP",
+	"api":  "API",
+	"url":  "URL",
+	"uri":  "URI",
+	"uuid": "UUID",
+	"http": "HTTP",
+	"json": "JSON",
+	"sql":  "SQL",
+}
+
+// parseFields 解析命令行中的字段定义列表
+func parseFields(specs []string) ([]field, error) {
+	fields := make([]field, 0, len(specs))
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		f, err := parseField(spec)
+		if err != nil {
+			return nil, err
+		}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("duplicate field %q", f.Snake)
+		}
+		seen[f.Name] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// parseField 解析单个字段定义 name:type[:option...]
+func parseField(spec string) (field, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return field{}, fmt.Errorf("invalid field %q, expected name:type[:option...]", spec)
+	}
+
+	snake := utility.ToSnakeCase(parts[0])
+	f := field{
+		Name:  fieldName(snake),
+		Snake: snake,
+		Type:  parts[1],
+	}
+	if !token.IsIdentifier(f.Name) {
+		return field{}, fmt.Errorf("invalid field name %q in %q", parts[0], spec)
+	}
+	if goType, ok := fieldTypes[f.Type]; ok {
+		f.Type = goType
+	}
+	if strings.ContainsAny(f.Type, " \t\"`{}()") {
+		return field{}, fmt.Errorf("invalid field type %q in %q", parts[1], spec)
+	}
+
+	for _, opt := range parts[2:] {
+		if !slices.Contains(fieldOptions, opt) {
+			return field{}, fmt.Errorf("unknown field option %q in %q (supported: %s)", opt, spec, strings.Join(fieldOptions, ", "))
+		}
+		if !slices.Contains(f.Options, opt) {
+			f.Options = append(f.Options, opt)
+		}
+	}
+	if f.Has("null") && !strings.HasPrefix(f.Type, "*") {
+		f.Type = "*" + f.Type
+	}
+	return f, nil
+}
+
+// Has 判断字段是否包含指定选项
+func (f field) Has(opt string) bool {
+	return slices.Contains(f.Options, opt)
+}
+
+// IsInteger 判断字段是否为整数类型
+func (f field) IsInteger() bool {
+	switch f.Type {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+// IsUnsigned 判断字段是否为无符号整数类型
+func (f field) IsUnsigned() bool {
+	return f.IsInteger() && strings.HasPrefix(f.Type, "uint")
+}
+
+// BitSize 返回整数类型的位数，用作 strconv.ParseInt / ParseUint 的 bitSize，int 与 uint 返回 0
+func (f field) BitSize() int {
+	_, size, _ := strings.Cut(f.Type, "int")
+	n, _ := strconv.Atoi(size)
+	return n
+}
+
+// Tag 返回字段的 struct tag，如 json:"email" db:"email,unique"
+func (f field) Tag() string {
+	db := f.Snake
+	for _, opt := range f.Options {
+		if opt != "null" {
+			db += "," + opt
+		}
+	}
+	json := f.Snake
+	if f.Has("null") {
+		json += ",omitempty"
+	}
+	return fmt.Sprintf(`json:"%s" db:"%s"`, json, db)
+}
+
+// Param 返回字段在构造函数参数中使用的变量名
+func (f field) Param() string {
+	param := lowerFirst(f.Name)
+	if upper, ok := initialisms[strings.ToLower(f.Name)]; ok && upper == f.Name {
+		param = strings.ToLower(f.Name)
+	}
+	if token.IsKeyword(param) {
+		param += "Value"
+	}
+	return param
+}
+
+// idField 返回名为 id 的字段，不存在时返回 nil
+func idField(fields []field) *field {
+	for i := range fields {
+		if fields[i].Snake == "id" {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// fieldName 将蛇形命名转为 Go 字段名，并保持常见缩写全大写
+func fieldName(snake string) string {
+	parts := strings.Split(snake, "_")
+	for i, part := range parts {
+		if upper, ok := initialisms[part]; ok {
+			parts[i] = upper
+			continue
+		}
+		parts[i] = utility.ToCamelCase(part)
+	}
+	return strings.Join(parts, "")
+}
+
+// fieldImports 返回字段类型需要引入的包，按字母序排列
+func fieldImports(fields []field) []string {
+	var imports []string
+	for _, f := range fields {
+		for _, match := range typeQualifierPattern.FindAllStringSubmatch(f.Type, -1) {
+			if pkg, ok := typeImports[match[1]]; ok && !slices.Contains(imports, pkg) {
+				imports = append(imports, pkg)
+			}
+		}
+	}
+	slices.Sort(imports)
+	return imports
+}
+
+// fieldParams 返回构造函数的参数列表，如 name string, age int
+func fieldParams(fields []field) string {
+	params := make([]string, len(fields))
+	for i, f := range fields {
+		params[i] = f.Param() + " " + f.Type
+	}
+	return strings.Join(params, ", ")
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		spec  string
+		name  string
+		typ   string
+		tag   string
+		param string
+	}{
+		{"name:string", "Name", "string", `json:"name" db:"name"`, "name"},
+		{"email:string:unique", "Email", "string", `json:"email" db:"email,unique"`, "email"},
+		{"id:int64:pk", "ID", "int64", `json:"id" db:"id,pk"`, "id"},
+		{"createdAt:time", "CreatedAt", "time.Time", `json:"created_at" db:"created_at"`, "createdAt"},
+		{"deleted_at:datetime:null", "DeletedAt", "*time.Time", `json:"deleted_at,omitempty" db:"deleted_at"`, "deletedAt"},
+		{"user_id:int64:index", "UserID", "int64", `json:"user_id" db:"user_id,index"`, "userID"},
+		{"id:int64", "ID", "int64", `json:"id" db:"id"`, "id"},
+		{"type:string", "Type", "string", `json:"type" db:"type"`, "typeValue"},
+		{"tags:[]string", "Tags", "[]string", `json:"tags" db:"tags"`, "tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			f, err := parseField(tt.spec)
+			if err != nil {
+				t.Fatalf("parseField failed: %v", err)
+			}
+			if f.Name != tt.name || f.Type != tt.typ {
+				t.Errorf("parseField = %s %s; want %s %s", f.Name, f.Type, tt.name, tt.typ)
+			}
+			if got := f.Tag(); got != tt.tag {
+				t.Errorf("Tag() = %s; want %s", got, tt.tag)
+			}
+			if got := f.Param(); got != tt.param {
+				t.Errorf("Param() = %s; want %s", got, tt.param)
+			}
+		})
+	}
+}
+
+func TestParseFieldInvalid(t *testing.T) {
+	specs := []string{"name", "name:", ":string", "name:string:bogus", "1st:int", "name:map[string]struct{}"}
+	for _, spec := range specs {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := parseField(spec); err == nil {
+				t.Errorf("expected error for %q", spec)
+			}
+		})
+	}
+}
+
+func TestParseFieldsDuplicate(t *testing.T) {
+	if _, err := parseFields([]string{"name:string", "name:int"}); err == nil {
+		t.Fatal("expected error for duplicate field")
+	}
+}
+
+func TestFieldImports(t *testing.T) {
+	fields, err := parseFields([]string{"a:time", "b:json", "c:datetime:null", "d:string"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := fieldImports(fields)
+	want := []string{"encoding/json", "time"}
+	if !slices.Equal(got, want) {
+		t.Errorf("fieldImports = %v; want %v", got, want)
+	}
+	if params := fieldParams(fields[:2]); params != "a time.Time, b json.RawMessage" {
+		t.Errorf("fieldParams = %q", params)
+	}
+
+	// 只匹配完整的包限定符
+	got = fieldImports([]field{{Type: "mysql.NullTime"}, {Type: "xtime.Duration"}, {Type: "pgsql.X"}})
+	if len(got) != 0 {
+		t.Errorf("fieldImports = %v; want none", got)
+	}
+	got = fieldImports([]field{{Type: "*sql.NullString"}, {Type: "map[string][]time.Time"}})
+	if want := []string{"database/sql", "time"}; !slices.Equal(got, want) {
+		t.Errorf("fieldImports = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxFormatErrors 是格式化失败时最多展示的语法错误数量
+const maxFormatErrors = 10
+
+// formatGoSource 格式化生成的 Go 源码：
+// 1. 解析源码，失败时返回带行号与源码行的错误
+// 2. 将导入按标准库、第三方、本模块分组
+// 3. 使用 go/format 格式化（组内导入按字母序排列）
+func formatGoSource(filename string, src []byte, module string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, annotateSyntaxError(filename, src, err)
+	}
+	src = groupImports(fset, file, src, module)
+	out, err := format.Source(src)
+	if err != nil {
+		return nil, annotateSyntaxError(filename, src, err)
+	}
+	return out, nil
+}
+
+// annotateSyntaxError 为语法错误附加出错的源码行
+func annotateSyntaxError(filename string, src []byte, err error) error {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) {
+		return fmt.Errorf("%s is not valid Go: %w", filename, err)
+	}
+	lines := strings.Split(string(src), "\n")
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s is not valid Go:", filename)
+	for i, e := range list {
+		if i == maxFormatErrors {
+			fmt.Fprintf(&sb, "\n\t... and %d more error(s)", len(list)-maxFormatErrors)
+			break
+		}
+		fmt.Fprintf(&sb, "\n\t%d:%d: %s", e.Pos.Line, e.Pos.Column, e.Msg)
+		if e.Pos.Line >= 1 && e.Pos.Line <= len(lines) {
+			fmt.Fprintf(&sb, "\n\t%5d | %s", e.Pos.Line, lines[e.Pos.Line-1])
+		}
+	}
+	return errors.New(sb.String())
+}
+
+// importGroup 返回导入路径所属分组：0 标准库，1 第三方，2 本模块
+func importGroup(importPath, module string) int {
+	if module != "" && (importPath == module || strings.HasPrefix(importPath, module+"/")) {
+		return 2
+	}
+	first, _, _ := strings.Cut(importPath, "/")
+	if !strings.Contains(first, ".") {
+		return 0
+	}
+	return 1
+}
+
+// groupImports 将第一个分组导入声明按标准库、第三方、本模块重新分组
+// 导入中带有注释时保持原样，避免丢失注释
+func groupImports(fset *token.FileSet, file *ast.File, src []byte, module string) []byte {
+	var decl *ast.GenDecl
+	for _, d := range file.Decls {
+		if gen, ok := d.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			decl = gen
+			break
+		}
+	}
+	if decl == nil || !decl.Lparen.IsValid() || len(decl.Specs) < 2 {
+		return src
+	}
+	start, end := fset.Position(decl.Lparen).Offset, fset.Position(decl.Rparen).Offset
+	for _, c := range file.Comments {
+		if offset := fset.Position(c.Pos()).Offset; offset > start && offset < end {
+			return src
+		}
+	}
+
+	groups := make([][]string, 3)
+	for _, spec := range decl.Specs {
+		imp := spec.(*ast.ImportSpec)
+		importPath, _ := strconv.Unquote(imp.Path.Value)
+		line := imp.Path.Value
+		if imp.Name != nil {
+			line = imp.Name.Name + " " + line
+		}
+		g := importGroup(importPath, module)
+		groups[g] = append(groups[g], line)
+	}
+
+	var block bytes.Buffer
+	block.WriteString("(\n")
+	first := true
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		if !first {
+			block.WriteString("\n")
+		}
+		first = false
+		sort.Strings(group)
+		for _, line := range group {
+			block.WriteString("\t" + line + "\n")
+		}
+	}
+	block.WriteString(")")
+
+	out := make([]byte, 0, len(src)+block.Len())
+	out = append(out, src[:start]...)
+	out = append(out, block.Bytes()...)
+	return append(out, src[end+1:]...)
+}
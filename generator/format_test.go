@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatGoSource(t *testing.T) {
+	src := `package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"example.com/app/internal/service"
+	"github.com/rushairer/gouno"
+	"context"
+)
+
+type   Foo struct {
+A int
+	LongName string
+}
+`
+	out, err := formatGoSource("foo.go", []byte(src), "example.com/app")
+	if err != nil {
+		t.Fatalf("formatGoSource failed: %v", err)
+	}
+	want := `package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rushairer/gouno"
+
+	"example.com/app/internal/service"
+)
+
+type Foo struct {
+	A        int
+	LongName string
+}
+`
+	if string(out) != want {
+		t.Errorf("formatGoSource =\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestFormatGoSourceKeepsImportComments(t *testing.T) {
+	src := "package a\n\nimport (\n\t\"net/http\" // server\n\t\"context\"\n)\n"
+	out, err := formatGoSource("a.go", []byte(src), "")
+	if err != nil {
+		t.Fatalf("formatGoSource failed: %v", err)
+	}
+	if !strings.Contains(string(out), "// server") {
+		t.Errorf("import comment lost:\n%s", out)
+	}
+}
+
+func TestFormatGoSourceSyntaxError(t *testing.T) {
+	src := "package a\n\nfunc Foo() {\n\treturn 1 +\n}\n"
+	_, err := formatGoSource("a.go", []byte(src), "")
+	if err == nil {
+		t.Fatal("expected syntax error")
+	}
+	msg := err.Error()
+	for _, want := range []string{"a.go is not valid Go", "5:1:", "    5 | }"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error does not contain %q:\n%s", want, msg)
+		}
+	}
+}
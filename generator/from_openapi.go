@@ -0,0 +1,209 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rushairer/gouno/utility"
+	"github.com/spf13/cobra"
+)
+
+var fromOpenAPICmd = &cobra.Command{
+	Use:                   "from-openapi [api.yaml]",
+	Short:                 "Generate controllers and DTOs from an OpenAPI 3 document",
+	Args:                  cobra.ExactArgs(1),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read OpenAPI document: %w", err)
+		}
+		doc, err := parseOpenAPI(src)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", args[0], err)
+		}
+		builder, tags, operations, err := buildOpenAPI(doc)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", args[0], err)
+		}
+		if len(tags) == 0 {
+			return fmt.Errorf("no operations found in %s", args[0])
+		}
+
+		projectRoot, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		templateSet := resolveTemplateSet(cmd)
+		manifest, err := loadManifest(templateSet)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(projectRoot, outputPath(cmd, manifest, "controller", defaultControllerPath))
+		module := readModulePath(projectRoot)
+		newData := func(name string, api *apiSpec) *templateData {
+			structName := utility.ToCamelCase(name)
+			return &templateData{
+				Name:        name,
+				StructName:  structName,
+				Snake:       utility.ToSnakeCase(structName),
+				Package:     packageName(dir, "controller"),
+				Module:      module,
+				TemplateSet: templateSet,
+				Timestamp:   time.Now(),
+				Packages:    layerImports(module, manifest, ""),
+				API:         api,
+			}
+		}
+
+		// 先生成所有控制器用到的类型，再逐个标签生成控制器并注册路由；任一步失败时还原已生成的文件与路由注册
+		specName := utility.ToSnakeCase(goTypeName(strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))))
+		return inTransaction(cmd, func() error {
+			if len(builder.types) > 0 {
+				data := newData(specName, &apiSpec{
+					Title:   doc.Info.Title,
+					Version: doc.Info.Version,
+					Types:   builder.types,
+					Imports: builder.imports(),
+				})
+				if _, err := writeTemplateFile(cmd, "dto", "openapi_dto", filepath.Join(dir, specName+"_dto.go"), data); err != nil {
+					return err
+				}
+			}
+
+			for _, tag := range tags {
+				name := utility.ToSnakeCase(goTypeName(tag))
+				api := &apiSpec{
+					Title:      doc.Info.Title,
+					Version:    doc.Info.Version,
+					Tag:        tag,
+					Doc:        tagDescription(doc, tag),
+					Operations: operations[tag],
+				}
+				controller, err := writeTemplateFile(cmd, "controller", "openapi_controller", filepath.Join(dir, name+".go"), newData(name, api))
+				if err != nil {
+					return err
+				}
+
+				reg := newRouteRegistration(controller)
+				reg.Group, reg.Routes = operationRoutes(doc.serverBasePath(), api.Operations)
+				if err := registerRoute(cmd, reg); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	fromOpenAPICmd.Flags().StringP("path", "p", defaultControllerPath, "path to controllers and DTOs")
+	fromOpenAPICmd.Flags().BoolP("force", "f", false, "force overwrite")
+	fromOpenAPICmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(fromOpenAPICmd)
+	fromOpenAPICmd.Flags().Bool("no-route", false, "skip route registration in the configured router file")
+}
+
+func tagDescription(doc *openapiDoc, tag string) string {
+	for _, t := range doc.Tags {
+		if t.Name == tag {
+			return t.Description
+		}
+	}
+	return ""
+}
+
+// operationRoutes 返回控制器的路由分组与各处理函数在分组内的路由
+// 分组为 server URL 的路径加上所有操作共同的静态前缀
+func operationRoutes(basePath string, operations []*apiOperation) (string, map[string][2]string) {
+	var paths []string
+	for _, op := range operations {
+		paths = append(paths, op.Route)
+	}
+	prefix := commonRoutePrefix(paths)
+
+	routes := make(map[string][2]string, len(operations))
+	for _, op := range operations {
+		route := strings.TrimPrefix(op.Route, prefix)
+		if route == "/" {
+			route = ""
+		}
+		routes[op.Handler] = [2]string{op.Method, route}
+	}
+	return cmp.Or(basePath+prefix, "/"), routes
+}
+
+const openapiDTOTemplate = `// Code generated by gouno gen from-openapi{{with .API.Title}} from {{.}}{{end}}{{with .API.Version}} {{.}}{{end}}; DO NOT EDIT.
+
+package {{.Package}}
+{{- if .API.Imports}}
+
+import (
+{{- range .API.Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{- end}}
+{{range .API.Types}}
+{{- if .Doc}}
+{{comment .Doc}}
+{{- end}}
+{{- if .Fields}}
+type {{.Name}} struct {
+{{- range .Fields}}
+{{- if .Doc}}
+	{{comment .Doc}}
+{{- end}}
+	{{.Name}} {{.Type}}{{with .Tag}} ` + "`{{.}}`" + `{{end}}
+{{- end}}
+}
+{{- else}}
+type {{.Name}} {{.Underlying}}
+{{- end}}
+{{end}}`
+
+const openapiControllerTemplate = `package {{.Package}}
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rushairer/gouno"
+)
+
+// {{.StructName}}Controller handles the {{.API.Tag}} operations{{with .API.Title}} of {{.}}{{end}}.
+{{- if .API.Doc}}
+{{comment .API.Doc}}
+{{- end}}
+type {{.StructName}}Controller struct {
+}
+
+func New{{.StructName}}Controller() *{{.StructName}}Controller {
+	return &{{.StructName}}Controller{}
+}
+{{range .API.Operations}}
+// {{.Handler}} handles {{.Method}} {{.Path}}.
+{{- if .Summary}}
+{{comment .Summary}}
+{{- end}}
+func (c *{{$.StructName}}Controller) {{.Handler}}(ctx *gin.Context) {
+{{- range .Params}}
+	var {{.Var}} {{.Type}}
+	if err := ctx.{{.Method}}(&{{.Var}}); err != nil {
+		ctx.JSON(http.StatusBadRequest, gouno.NewBadRequestResponse())
+		return
+	}
+{{- end}}
+{{- if .Params}}
+{{end}}
+	// TODO: implement {{.Handler}}
+{{- if .Result}}
+	var resp {{.Result}}
+{{- end}}
+	ctx.JSON({{.Status}}, {{if .OK}}gouno.NewSuccessResponse({{else}}gouno.NewResponse({{.Status}}, "success", {{end}}{{if .Result}}resp{{else}}nil{{end}}))
+}
+{{end}}`
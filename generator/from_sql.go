@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rushairer/gouno/utility"
+	"github.com/spf13/cobra"
+)
+
+var fromSQLCmd = &cobra.Command{
+	Use:                   "from-sql [schema.sql]",
+	Short:                 "Generate suites (domain, repository, service) from CREATE TABLE statements",
+	Args:                  cobra.ExactArgs(1),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read schema: %w", err)
+		}
+		tables, err := parseSQLTables(string(src))
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", args[0], err)
+		}
+		if len(tables) == 0 {
+			return fmt.Errorf("no CREATE TABLE statements found in %s", args[0])
+		}
+
+		only, _ := cmd.Flags().GetStringSlice("table")
+		for _, name := range only {
+			if !slices.ContainsFunc(tables, func(t sqlTable) bool { return strings.EqualFold(t.Name, name) }) {
+				return fmt.Errorf("table %q not found in %s", name, args[0])
+			}
+		}
+		names, err := tableNames(cmd, tables)
+		if err != nil {
+			return fmt.Errorf("%w in %s", err, args[0])
+		}
+		for _, table := range tables {
+			if len(only) > 0 && !slices.ContainsFunc(only, func(name string) bool { return strings.EqualFold(table.Name, name) }) {
+				continue
+			}
+			name, ok := names[strings.ToLower(table.Name)]
+			if !ok {
+				name = utility.ToSnakeCase(singularize(table.Name))
+			}
+			cmd.Printf("Generating suite %s from table %s\n", name, table.Name)
+			if err := generateSuite(cmd, append([]string{name}, tableFields(cmd, table)...)); err != nil {
+				return fmt.Errorf("table %s: %w", table.Name, err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	fromSQLCmd.Flags().StringSlice("table", nil, "only generate the given tables (repeatable)")
+	fromSQLCmd.Flags().StringArray("name", nil, "set the resource name of a table as table=name instead of its singular form (repeatable)")
+	fromSQLCmd.Flags().BoolP("force", "f", false, "force overwrite")
+	fromSQLCmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(fromSQLCmd)
+}
+
+// tableNames 返回 --name 为表指定的资源名称，以小写的表名为键
+func tableNames(cmd *cobra.Command, tables []sqlTable) (map[string]string, error) {
+	values, _ := cmd.Flags().GetStringArray("name")
+	names := make(map[string]string, len(values))
+	for _, assignment := range values {
+		table, name, ok := strings.Cut(assignment, "=")
+		if !ok || table == "" || name == "" {
+			return nil, fmt.Errorf("invalid --name %q, expected table=name", assignment)
+		}
+		if !slices.ContainsFunc(tables, func(t sqlTable) bool { return strings.EqualFold(t.Name, table) }) {
+			return nil, fmt.Errorf("--name: table %q not found", table)
+		}
+		names[strings.ToLower(table)] = name
+	}
+	return names, nil
+}
+
+// tableFields 将表的各列转为字段定义，无法识别的列类型按 string 处理并给出提示
+func tableFields(cmd *cobra.Command, table sqlTable) []string {
+	var args []string
+	for _, col := range table.Columns {
+		typ, ok := col.goFieldType()
+		if !ok {
+			cmd.Printf("Unsupported type %q for column %s.%s, using string\n", col.Type, table.Name, col.Name)
+			typ = "string"
+		}
+		args = append(args, col.fieldSpec(typ))
+	}
+	return args
+}
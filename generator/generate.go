@@ -2,8 +2,12 @@ package generator
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/rushairer/gouno/utility"
 	"github.com/spf13/cobra"
@@ -11,48 +15,219 @@ import (
 
 // generateFile 是所有代码生成器的公共逻辑：
 // 1. 确定模板集并加载模板
-// 2. 将名称转为驼峰命名
-// 3. 在目标目录下创建文件
-// 4. 若文件已存在且未指定 --force，跳过并提示
+// 2. 将名称转为驼峰命名，并解析 name:type[:option...] 字段定义
+// 3. 使用 text/template 渲染模板（兼容旧式 %s 模板），并格式化生成的 Go 代码
+// 4. 写入目标目录，若文件已存在且未指定 --force，跳过并提示
+// 5. 指定 --dry-run / --diff 时只报告将要执行的操作
+// 6. 指定 --with-test 时使用 <type>_test 模板生成 <name>_test.go
 func generateFile(cmd *cobra.Command, args []string, typeName, defaultPath string) error {
+	_, err := generateTemplateFile(cmd, args, typeName, typeName, defaultPath)
+	return err
+}
+
+// generatedFile 记录一次模板生成的结果，文件因已存在被跳过时同样返回
+type generatedFile struct {
+	TypeName   string
+	Path       string // 生成文件的绝对路径
+	ImportPath string // 所在包的导入路径，模块路径未知时为空
+	Data       *templateData
+	Content    string         // 渲染后的内容
+	Action     string         // 执行（或 --dry-run 下将要执行）的操作，见 actionCreated 等
+	Test       *generatedFile // 指定 --with-test 时一并生成的测试文件
+
+	// Siblings 是目录模板同时生成的其他文件，单文件模板为空
+	Siblings []*generatedFile
+	// fromDirectory 表示文件由目录模板生成，此时测试文件由目录中的 *_test.go 模板提供
+	fromDirectory bool
+}
+
+// files 返回本次生成的所有文件，包括目录模板生成的其他文件
+func (f *generatedFile) files() []*generatedFile {
+	return append([]*generatedFile{f}, f.Siblings...)
+}
+
+// generateTemplateFile 与 generateFile 相同，但使用 templateName 指定的模板生成 typeName 类型的文件，
+// 并返回生成结果。例如 crud 使用 crud_repository 模板生成 repository 文件
+func generateTemplateFile(cmd *cobra.Command, args []string, typeName, templateName, defaultPath string) (*generatedFile, error) {
 	templateSet := resolveTemplateSet(cmd)
-	tmpl, err := loadTemplate(cmd, templateSet, typeName)
+	if _, err := loadTemplateFiles(cmd, templateSet, templateName); err != nil {
+		return nil, err
+	}
+	if err := validateName(args[0], typeName); err != nil {
+		return nil, err
+	}
+	data, filePath, err := newTemplateData(cmd, args, typeName, defaultPath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := writeTemplateFile(cmd, typeName, templateName, filePath, data)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if withTest(cmd) && !file.fromDirectory {
+		if file.Test, err = generateTestFile(cmd, file, templateSet, templateName); err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// newTemplateData 根据名称与字段定义构造 typeName 类型的模板数据，并返回主文件的生成路径
+func newTemplateData(cmd *cobra.Command, args []string, typeName, defaultPath string) (*templateData, string, error) {
+	templateSet := resolveTemplateSet(cmd)
+	manifest, err := loadManifest(templateSet)
+	if err != nil {
+		return nil, "", err
 	}
 
 	name := args[0]
-	structName := utility.ToCamelCase(name)
+	group, base := splitName(name)
+	structName := structName(name)
+	fields, err := parseFields(args[1:])
+	if err != nil {
+		return nil, "", err
+	}
 
 	projectRoot, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get current working directory: %w", err)
+		return nil, "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	// 嵌套名称（如 admin/user）生成在目标目录的分组子目录中
+	dir := filepath.Join(projectRoot, outputPath(cmd, manifest, typeName, defaultPath), filepath.FromSlash(group))
+
+	module := readModulePath(projectRoot)
+	data := &templateData{
+		Name:        name,
+		Group:       group,
+		StructName:  structName,
+		Snake:       utility.ToSnakeCase(structName),
+		Package:     packageName(dir, typeName),
+		Module:      module,
+		TemplateSet: templateSet,
+		Timestamp:   time.Now(),
+		Fields:      fields,
+		Imports:     fieldImports(fields),
+		ID:          idField(fields),
+		Packages:    layerImports(module, manifest, group),
+		args:        args,
+	}
+	return data, filepath.Join(dir, base+".go"), nil
+}
+
+// writeTemplateFile 使用 data.TemplateSet 中的 templateName 模板渲染 data，格式化后写入 filePath
+// 模板为目录时，目录中的每个文件按渲染后的文件名写入 filePath 所在目录，
+// 其中渲染为 filePath 的文件（没有时为第一个文件）作为返回结果，其余文件记录在 Siblings 中；
+// *_test.go 文件只在指定 --with-test 时生成
+func writeTemplateFile(cmd *cobra.Command, typeName, templateName, filePath string, data *templateData) (*generatedFile, error) {
+	templates, err := loadTemplateFiles(cmd, data.TemplateSet, templateName)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := loadManifest(data.TemplateSet)
+	if err != nil {
+		return nil, err
+	}
+	if data.Vars, err = templateVariables(cmd, data.TemplateSet, manifest); err != nil {
+		return nil, err
+	}
+
+	// 先渲染并检查全部文件名，避免写入部分文件后才发现错误
+	paths, err := templateFilePaths(templateName, templates, filePath, data, withTest(cmd))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%s template of set %q only contains test files (use --with-test)", templateName, data.TemplateSet)
 	}
 
-	path := defaultPath
-	if flag := cmd.Flag("path"); flag != nil {
-		path = flag.Value.String()
+	var files []*generatedFile
+	var sources []templateFile
+	for _, tmpl := range templates {
+		path, ok := paths[tmpl.Name]
+		if !ok {
+			continue
+		}
+		file, err := renderTemplateFile(cmd, typeName, templateName+"/"+tmpl.Name, tmpl.Content, path, data)
+		if err != nil {
+			return nil, err
+		}
+		file.fromDirectory = tmpl.Name != ""
+		files = append(files, file)
+		sources = append(sources, tmpl)
 	}
-	dir := filepath.Join(projectRoot, path)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create %s directory: %w", typeName, err)
+	for _, file := range files {
+		if err := checkDeclarations(file, slices.Collect(maps.Values(paths))); err != nil {
+			return nil, err
+		}
+	}
+	for i, file := range files {
+		if file.Action, err = writeFile(cmd, typeName, file.Path, file.Content); err != nil {
+			return nil, err
+		}
+		if err := recordLock(cmd, file, templateName, sources[i].Name, sources[i].Content, filepath.Dir(filePath)); err != nil {
+			return nil, err
 		}
-		cmd.Printf("Created directory: %s\n", dir)
 	}
 
-	filePath := filepath.Join(dir, fmt.Sprintf("%s.go", name))
-	if force, _ := cmd.Flags().GetBool("force"); !force {
-		if _, err := os.Stat(filePath); err == nil {
-			cmd.Printf("%s file already exists, skipping: %s (use --force to overwrite)\n", typeName, filePath)
-			return nil
+	primary := slices.IndexFunc(files, func(f *generatedFile) bool { return f.Path == filePath })
+	if primary < 0 {
+		primary = 0
+	}
+	file := files[primary]
+	file.Siblings = slices.Delete(files, primary, primary+1)
+	return file, nil
+}
+
+// templateFilePaths 渲染模板中各文件的文件名，返回模板文件名到生成路径的映射（单文件模板的文件名为空，对应 filePath）
+// withTests 为 false 时不包含 *_test.go 文件
+func templateFilePaths(templateName string, templates []templateFile, filePath string, data *templateData, withTests bool) (map[string]string, error) {
+	paths := make(map[string]string)
+	for _, tmpl := range templates {
+		if tmpl.Name == "" {
+			paths[tmpl.Name] = filePath
+			continue
+		}
+		name, err := renderTemplate(templateName+"/"+tmpl.Name, tmpl.Name, data)
+		if err != nil {
+			return nil, err
+		}
+		name = strings.TrimSuffix(name, templateExt)
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return nil, fmt.Errorf("template %s/%s renders to %q outside the output directory", templateName, tmpl.Name, name)
 		}
+		if strings.HasSuffix(name, "_test.go") && !withTests {
+			continue
+		}
+		paths[tmpl.Name] = filepath.Join(filepath.Dir(filePath), filepath.FromSlash(name))
 	}
+	return paths, nil
+}
 
-	content := fmt.Sprintf(tmpl, structName, structName, structName, structName, structName)
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to create %s file: %w", typeName, err)
+// renderTemplateFile 渲染将要写入 filePath 的单个模板文件，Go 源码会被格式化
+func renderTemplateFile(cmd *cobra.Command, typeName, templateName, tmpl, filePath string, data *templateData) (*generatedFile, error) {
+	content, err := renderTemplate(strings.TrimSuffix(templateName, "/"), tmpl, data)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(filePath, ".go") {
+		formatted, err := formatGoSource(filePath, []byte(content), data.Module)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s file: %w", typeName, err)
+		}
+		content = string(formatted)
+	}
+
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
 	}
-	cmd.Printf("Created %s file: %s\n", typeName, filePath)
-	return nil
+	return &generatedFile{
+		TypeName:   typeName,
+		Path:       filePath,
+		ImportPath: dirImportPath(data.Module, projectRoot, filepath.Dir(filePath)),
+		Data:       data,
+		Content:    content,
+	}, nil
 }
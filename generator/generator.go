@@ -6,7 +6,21 @@ import (
 
 // GeneratorCmd is the root Cobra command for the code generator.
 // It provides subcommands to scaffold DDD layers: domain, repository, service,
-// controller, task, and suite (all three domain layers at once).
+// controller, task, suite (all three domain layers at once), crud
+// (a full CRUD scaffold across domain, repository, service and controller),
+// mock (a fake of an existing repository or service), from-sql
+// (suites derived from CREATE TABLE statements), from-openapi
+// (controllers and DTOs derived from an OpenAPI 3 document), template
+// (listing, installing and ejecting template sets), destroy (removing
+// unmodified generated files and their router registrations), status
+// (generated files that drifted from or lag behind their templates, as
+// recorded in .gouno.lock), upgrade (re-rendering files whose template
+// changed), and apply (generating every resource listed in a spec file).
+// Template sets may declare additional kinds in
+// their template.yaml, which Register adds as subcommands; add GeneratorCmd
+// with Register rather than AddCommand so that they can be invoked directly.
+// The persistent hooks of the command it is added to still run for its
+// subcommands.
 // Aliases: "gen".
 var GeneratorCmd = &cobra.Command{
 	Use:     "generator",
@@ -14,6 +28,40 @@ var GeneratorCmd = &cobra.Command{
 	Aliases: []string{"gen"},
 }
 
+// Register adds GeneratorCmd to parent, together with a subcommand for every
+// kind declared by the template sets visible from the current directory and
+// home directory. Call it when the CLI starts, before executing parent.
+// A CLI that adds GeneratorCmd with AddCommand still runs the builtin
+// subcommands, and apply still generates custom kinds, but `gen <kind>` is
+// only available through Register.
+func Register(parent *cobra.Command) {
+	parent.AddCommand(GeneratorCmd)
+	registerKindCommands()
+}
+
+// runParentHook 执行 GeneratorCmd 上层命令中最近的持久钩子（pre 为 true 时为 PersistentPreRun，否则为 PersistentPostRun）：
+// cobra 默认只执行离子命令最近的持久钩子，GeneratorCmd 自身的钩子会遮蔽宿主 CLI 的钩子；
+// 启用 cobra.EnableTraverseRunHooks 时 cobra 会自行执行所有层级的钩子
+func runParentHook(cmd *cobra.Command, args []string, pre bool) error {
+	if cobra.EnableTraverseRunHooks {
+		return nil
+	}
+	for p := GeneratorCmd.Parent(); p != nil; p = p.Parent() {
+		runE, run := p.PersistentPostRunE, p.PersistentPostRun
+		if pre {
+			runE, run = p.PersistentPreRunE, p.PersistentPreRun
+		}
+		if runE != nil {
+			return runE(cmd, args)
+		}
+		if run != nil {
+			run(cmd, args)
+			return nil
+		}
+	}
+	return nil
+}
+
 func init() {
 	GeneratorCmd.AddCommand(
 		controllerCmd,
@@ -22,5 +70,29 @@ func init() {
 		domainCmd,
 		suiteCmd,
 		taskCmd,
+		crudCmd,
+		mockCmd,
+		fromSQLCmd,
+		fromOpenAPICmd,
+		templateCmd,
+		destroyCmd,
+		statusCmd,
+		upgradeCmd,
+		applyCmd,
 	)
+
+	// 在 init 中设置以避免 GeneratorCmd 与 runHooks、runParentHook 之间的初始化循环
+	GeneratorCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		currentSession = newSession()
+		// 宿主 CLI 用 AddCommand 添加 GeneratorCmd 时没有调用 Register，此时补充注册自定义类型，使 apply 可以使用；
+		// 已注册的类型会被跳过
+		registerKindCommands()
+		return runParentHook(cmd, args, true)
+	}
+	GeneratorCmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		if err := runHooks(cmd, args); err != nil {
+			return err
+		}
+		return runParentHook(cmd, args, false)
+	}
 }
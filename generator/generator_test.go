@@ -1,14 +1,22 @@
 package generator_test
 
 import (
+	"archive/tar"
+	"archive/zip"
 	"bytes"
+	"compress/gzip"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/rushairer/gouno"
 	"github.com/rushairer/gouno/generator"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func executeCommandC(root *cobra.Command, args ...string) (c *cobra.Command, output string, err error) {
@@ -19,18 +27,25 @@ func executeCommandC(root *cobra.Command, args ...string) (c *cobra.Command, out
 	c, err = root.ExecuteC()
 
 	// 重置所有子命令的标志到默认值，防止跨测试状态污染
-	for _, subCmd := range root.Commands() {
-		if f := subCmd.Flag("path"); f != nil {
-			f.Value.Set(f.DefValue)
-		}
-		if f := subCmd.Flag("force"); f != nil {
-			f.Value.Set(f.DefValue)
-		}
-	}
+	resetFlags(root)
 
 	return c, buf.String(), err
 }
 
+func resetFlags(cmd *cobra.Command) {
+	for _, subCmd := range cmd.Commands() {
+		subCmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if v, ok := f.Value.(pflag.SliceValue); ok {
+				v.Replace(nil)
+			} else {
+				f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+		resetFlags(subCmd)
+	}
+}
+
 // chdir 切换到临时目录作为工作目录，测试结束后自动还原并清理
 func chdir(t *testing.T) string {
 	t.Helper()
@@ -117,6 +132,28 @@ func TestGeneratorDomain(t *testing.T) {
 	})
 }
 
+func TestGeneratorDomainFields(t *testing.T) {
+	tmpDir := chdir(t)
+
+	_, _, err := executeCommandC(generator.GeneratorCmd, "domain", "user", "name:string", "age:int", "email:string:unique", "created_at:time")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	filePath := filepath.Join(tmpDir, "internal", "domain", "user.go")
+	assertFileMatches(t, filePath, `Email\s+string\s+`+"`json:\"email\" db:\"email,unique\"`")
+	assertFileMatches(t, filePath, `CreatedAt\s+time\.Time`)
+	assertFileContains(t, filePath, `"time"`)
+	assertFileContains(t, filePath, "func NewUser(name string, age int, email string, createdAt time.Time) *User")
+	assertFileMatches(t, filePath, `Age:\s+age,`)
+
+	t.Run("invalid field", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "domain", "bad", "name")
+		if err == nil {
+			t.Fatal("expected error for invalid field spec")
+		}
+	})
+}
+
 func TestGeneratorRepository(t *testing.T) {
 	tmpDir := chdir(t)
 
@@ -216,6 +253,119 @@ func TestGeneratorController(t *testing.T) {
 	})
 }
 
+func TestGeneratorControllerRoute(t *testing.T) {
+	tmpDir := chdir(t)
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/app\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte("router:\n  file: router/router.go\n"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "router"), 0755)
+	routerPath := filepath.Join(tmpDir, "router", "router.go")
+	os.WriteFile(routerPath, []byte("package router\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc RegisterRoutes(r *gin.Engine) {\n}\n"), 0644)
+
+	_, output, err := executeCommandC(generator.GeneratorCmd, "controller", "auth")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	assertFileContains(t, routerPath, `"example.com/app/controller"`)
+	assertFileContains(t, routerPath, "authController := controller.NewAuthController()")
+	assertFileContains(t, routerPath, `authGroup.GET("/foo", authController.Foo)`)
+	if !strings.Contains(output, "Updated router file") {
+		t.Errorf("output should report router changes, got:\n%s", output)
+	}
+
+	_, output, err = executeCommandC(generator.GeneratorCmd, "controller", "auth")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if !strings.Contains(output, "Route already registered") {
+		t.Errorf("second run should skip registration, got:\n%s", output)
+	}
+}
+
+func TestGeneratorNestedNames(t *testing.T) {
+	tmpDir := chdir(t)
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/app\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte("router:\n  file: router/router.go\n"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "router"), 0755)
+	routerPath := filepath.Join(tmpDir, "router", "router.go")
+	os.WriteFile(routerPath, []byte("package router\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc RegisterRoutes(r *gin.Engine) {\n}\n"), 0644)
+
+	t.Run("controller in group package", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "controller", "admin/user")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		controllerPath := filepath.Join(tmpDir, "controller", "admin", "user.go")
+		assertFileContains(t, controllerPath, "package admin")
+		assertFileContains(t, controllerPath, "type AdminUserController struct")
+		assertFileContains(t, routerPath, `admincontroller "example.com/app/controller/admin"`)
+		assertFileContains(t, routerPath, "adminUserController := admincontroller.NewAdminUserController()")
+		assertFileContains(t, routerPath, `adminUserGroup := r.Group("/admin/user")`)
+	})
+
+	t.Run("crud imports nested layers", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "crud", "shop/order", "--no-route")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, filepath.Join(tmpDir, "internal", "domain", "shop", "order.go"), "type ShopOrder struct")
+		servicePath := filepath.Join(tmpDir, "internal", "service", "shop", "order.go")
+		assertFileContains(t, servicePath, "package shop")
+		assertFileContains(t, servicePath, `domain "example.com/app/internal/domain/shop"`)
+		assertFileContains(t, servicePath, `repository "example.com/app/internal/repository/shop"`)
+		assertFileContains(t, servicePath, "repository repository.ShopOrderRepository")
+	})
+
+	t.Run("short names", func(t *testing.T) {
+		os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte("short-nested-names: true\n"), 0644)
+		_, _, err := executeCommandC(generator.GeneratorCmd, "suite", "billing/invoice")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, filepath.Join(tmpDir, "internal", "domain", "billing", "invoice.go"), "type Invoice struct")
+		assertFileContains(t, filepath.Join(tmpDir, "internal", "service", "billing", "invoice.go"), "type InvoiceService struct")
+	})
+}
+
+func TestGeneratorNameValidation(t *testing.T) {
+	tmpDir := chdir(t)
+
+	t.Run("rejects keywords", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "domain", "type")
+		if err == nil || !strings.Contains(err.Error(), "Go keyword") {
+			t.Fatalf("expected keyword error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "domain", "type.go")); !os.IsNotExist(err) {
+			t.Errorf("nothing should be generated, got %v", err)
+		}
+	})
+
+	t.Run("suggests a valid name", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "task", "123-foo")
+		if err == nil || !strings.Contains(err.Error(), `try "foo_123"`) {
+			t.Fatalf("expected suggestion, got %v", err)
+		}
+	})
+
+	t.Run("existing declarations", func(t *testing.T) {
+		dir := filepath.Join(tmpDir, "internal", "service")
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "billing.go"), []byte("package service\n\ntype InvoiceService struct{}\n"), 0644)
+		_, _, err := executeCommandC(generator.GeneratorCmd, "service", "invoice")
+		if err == nil || !strings.Contains(err.Error(), "InvoiceService (internal/service/billing.go:3)") {
+			t.Fatalf("expected redeclaration error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "invoice.go")); !os.IsNotExist(err) {
+			t.Errorf("nothing should be generated, got %v", err)
+		}
+
+		// 其他平台的文件不会与生成的文件一起编译
+		os.Rename(filepath.Join(dir, "billing.go"), filepath.Join(dir, "billing_plan9.go"))
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "service", "invoice"); err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+	})
+}
+
 func TestGeneratorSuite(t *testing.T) {
 	tmpDir := chdir(t)
 
@@ -247,132 +397,1702 @@ func TestGeneratorSuite(t *testing.T) {
 		assertFileExists(t, filepath.Join(tmpDir, "internal", "repository", "foo.go"))
 		assertFileExists(t, filepath.Join(tmpDir, "internal", "service", "foo.go"))
 	})
+
+	t.Run("passes fields to every member", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "suite", "member", "name:string", "joined:time", "email:string:unique")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		repositoryPath := filepath.Join(tmpDir, "internal", "repository", "member.go")
+		assertFileContains(t, repositoryPath, `"time"`)
+		assertFileContains(t, repositoryPath, "func (r *MemberRepository) Create(ctx context.Context, name string, joined time.Time, email string) error")
+		assertFileContains(t, repositoryPath, "func (r *MemberRepository) ExistsByEmail(ctx context.Context, email string) (exists bool, err error)")
+		assertFileContains(t, filepath.Join(tmpDir, "internal", "service", "member.go"), "func (s *MemberService) Create(ctx context.Context, name string, joined time.Time, email string) error")
+	})
+
+	t.Run("honors --path", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "suite", "ticket", "--path", "modules/support")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileExists(t, filepath.Join(tmpDir, "modules", "support", "internal", "domain", "ticket.go"))
+		assertFileExists(t, filepath.Join(tmpDir, "modules", "support", "internal", "repository", "ticket.go"))
+		assertFileExists(t, filepath.Join(tmpDir, "modules", "support", "internal", "service", "ticket.go"))
+	})
 }
 
-func TestGeneratorAliases(t *testing.T) {
+func TestGeneratorSuiteKinds(t *testing.T) {
 	tmpDir := chdir(t)
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/shop\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte(`suites:
+  api:
+    - domain
+    - repository
+    - service
+    - type: controller
+      path: internal/http/controller
+    - task
+  broken:
+    - domain
+    - type: service
+      path: blocker/service
+  typo:
+    - domain
+    - widget
+`), 0644)
 
-	// 每个别名对应的实际路径（与 defaultPath 一致）
-	paths := map[string]string{
-		"c": filepath.Join("controller", "foo.go"),
-		"d": filepath.Join("internal", "domain", "foo.go"),
-		"r": filepath.Join("internal", "repository", "foo.go"),
-		"s": filepath.Join("internal", "service", "foo.go"),
-		"t": filepath.Join("internal", "task", "foo.go"),
-	}
-	pkgNames := map[string]string{
-		"c": "package controller",
-		"d": "package domain",
-		"r": "package repository",
-		"s": "package service",
-		"t": "package task",
-	}
+	t.Run("generates named suite with member paths", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "suite", "--kind", "api", "order")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileExists(t, filepath.Join(tmpDir, "internal", "domain", "order.go"))
+		assertFileExists(t, filepath.Join(tmpDir, "internal", "task", "order.go"))
+		controllerPath := filepath.Join(tmpDir, "internal", "http", "controller", "order.go")
+		assertFileContains(t, controllerPath, "type OrderController struct")
+	})
 
-	for alias, relPath := range paths {
-		t.Run(alias, func(t *testing.T) {
-			_, _, err := executeCommandC(generator.GeneratorCmd, alias, "foo")
-			if err != nil {
-				t.Fatalf("command %q failed: %v", alias, err)
-			}
-			assertFileContains(t, filepath.Join(tmpDir, relPath), pkgNames[alias])
-		})
-	}
-}
+	t.Run("rolls back on failure", func(t *testing.T) {
+		// blocker 是普通文件，服务目录无法创建
+		os.WriteFile(filepath.Join(tmpDir, "blocker"), nil, 0644)
+		_, output, err := executeCommandC(generator.GeneratorCmd, "suite", "-k", "broken", "refund")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		assertMatches(t, output, `Rolled back: .*refund\.go`)
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "domain", "refund.go")); !os.IsNotExist(err) {
+			t.Errorf("expected domain file to be rolled back, got %v", err)
+		}
+		assertFileExists(t, filepath.Join(tmpDir, "internal", "domain", "order.go"))
+	})
 
-func TestGeneratorNoArgs(t *testing.T) {
-	commands := []string{"service", "domain", "repository", "task", "controller"}
-	for _, name := range commands {
-		t.Run(name, func(t *testing.T) {
-			_, _, err := executeCommandC(generator.GeneratorCmd, name)
-			if err == nil {
-				t.Errorf("expected error for %q with no args, got nil", name)
-			}
-		})
-	}
+	t.Run("unknown member type", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "suite", "-k", "typo", "refund")
+		if err == nil || !strings.Contains(err.Error(), `unknown type "widget"`) {
+			t.Fatalf("expected unknown type error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "domain", "refund.go")); !os.IsNotExist(err) {
+			t.Errorf("expected nothing to be generated, got %v", err)
+		}
+	})
+
+	t.Run("unknown suite", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "suite", "-k", "nope", "refund")
+		if err == nil || !strings.Contains(err.Error(), "available: api, broken, default, typo") {
+			t.Fatalf("expected unknown suite error, got %v", err)
+		}
+	})
 }
 
-func TestGeneratorCamelCaseConversion(t *testing.T) {
+func TestGeneratorDestroy(t *testing.T) {
 	tmpDir := chdir(t)
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/shop\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte(`router:
+  file: router/router.go
+suites:
+  api: [domain, repository, service, controller]
+`), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "router"), 0755)
+	routerPath := filepath.Join(tmpDir, "router", "router.go")
+	os.WriteFile(routerPath, []byte("package router\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc RegisterRoutes(r *gin.Engine) {\n}\n"), 0644)
 
-	tests := []struct {
-		input    string
-		expected string
-	}{
-		{"foo_bar", "FooBar"},
-		{"my_service", "MyService"},
-		{"simple", "Simple"},
+	if _, _, err := executeCommandC(generator.GeneratorCmd, "suite", "--kind", "api", "user", "--with-test"); err != nil {
+		t.Fatalf("suite failed: %v", err)
 	}
+	domainPath := filepath.Join(tmpDir, "internal", "domain", "user.go")
+	servicePath := filepath.Join(tmpDir, "internal", "service", "user.go")
+	controllerPath := filepath.Join(tmpDir, "controller", "user.go")
+	f, _ := os.OpenFile(servicePath, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("\n// hand-written\n")
+	f.Close()
 
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			_, _, err := executeCommandC(generator.GeneratorCmd, "service", tt.input)
-			if err != nil {
-				t.Fatalf("command failed: %v", err)
+	t.Run("dry run", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "destroy", "suite", "user", "-k", "api", "--dry-run")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Would remove domain file: .*user\.go`)
+		assertFileExists(t, domainPath)
+		assertFileContains(t, routerPath, "userController")
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		router, _ := os.ReadFile(routerPath)
+		os.WriteFile(routerPath, append(router, "\nfunc broken(\n"...), 0644)
+		defer os.WriteFile(routerPath, router, 0644)
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "destroy", "suite", "user", "-k", "api")
+		if err == nil || !strings.Contains(err.Error(), "failed to update router file") {
+			t.Fatalf("expected router error, got %v", err)
+		}
+		assertMatches(t, output, `Rolled back: .*controller/user\.go`)
+		assertFileContains(t, controllerPath, "type UserController struct")
+		assertFileContains(t, filepath.Join(tmpDir, ".gouno.lock"), "controller/user.go:")
+		assertFileExists(t, filepath.Join(tmpDir, ".gouno", "cache", "controller", "user.go"))
+	})
+
+	t.Run("restores the router file", func(t *testing.T) {
+		// domain 最后删除，使其失败时路由注册已被移除
+		domain, _ := os.ReadFile(domainPath)
+		os.Remove(domainPath)
+		os.Mkdir(domainPath, 0755)
+		defer func() {
+			os.Remove(domainPath)
+			os.WriteFile(domainPath, domain, 0644)
+		}()
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "destroy", "suite", "user", "-k", "api")
+		if err == nil {
+			t.Fatal("expected error for unreadable domain file")
+		}
+		assertMatches(t, output, `Rolled back: .*router/router\.go`)
+		assertFileContains(t, routerPath, "userController")
+		assertFileContains(t, controllerPath, "type UserController struct")
+	})
+
+	t.Run("removes unmodified files and routes", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "destroy", "suite", "user", "-k", "api")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		for _, path := range []string{domainPath, controllerPath, strings.TrimSuffix(servicePath, ".go") + "_test.go"} {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("expected %s to be removed, got %v", path, err)
 			}
-			filePath := filepath.Join(tmpDir, "internal", "service", tt.input+".go")
-			assertFileContains(t, filePath, tt.expected)
-		})
-	}
+		}
+		assertMatches(t, output, `Kept modified service file: .*user\.go`)
+		assertFileContains(t, servicePath, "hand-written")
+		content, _ := os.ReadFile(routerPath)
+		if strings.Contains(string(content), "userController") || strings.Contains(string(content), "example.com/shop/controller") {
+			t.Errorf("router registration not removed:\n%s", content)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "domain")); !os.IsNotExist(err) {
+			t.Errorf("expected empty domain directory to be removed, got %v", err)
+		}
+	})
+
+	t.Run("force removes modified files", func(t *testing.T) {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "destroy", "service", "user", "--force"); err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if _, err := os.Stat(servicePath); !os.IsNotExist(err) {
+			t.Errorf("expected service file to be removed, got %v", err)
+		}
+	})
+
+	t.Run("force keeps files gouno did not generate", func(t *testing.T) {
+		taskPath := filepath.Join(tmpDir, "internal", "task", "manual.go")
+		os.MkdirAll(filepath.Dir(taskPath), 0755)
+		os.WriteFile(taskPath, []byte("package task\n"), 0644)
+		_, output, err := executeCommandC(generator.GeneratorCmd, "destroy", "task", "manual", "--force")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Kept task file with no recorded generated output: .*manual\.go`)
+		assertFileExists(t, taskPath)
+	})
+
+	t.Run("keeps layers imported by kept files", func(t *testing.T) {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "crud", "order", "amount:float"); err != nil {
+			t.Fatalf("crud failed: %v", err)
+		}
+		repositoryPath := filepath.Join(tmpDir, "internal", "repository", "order.go")
+		f, _ := os.OpenFile(repositoryPath, os.O_APPEND|os.O_WRONLY, 0644)
+		f.WriteString("\n// hand-written\n")
+		f.Close()
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "destroy", "crud", "order")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Kept modified repository file: .*repository/order\.go`)
+		assertMatches(t, output, `Kept domain file: .*domain/order\.go \(imported by kept file .*repository/order\.go\)`)
+		assertFileExists(t, filepath.Join(tmpDir, "internal", "domain", "order.go"))
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "service", "order.go")); !os.IsNotExist(err) {
+			t.Errorf("expected service file to be removed, got %v", err)
+		}
+	})
+
+	t.Run("nothing to remove", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "destroy", "domain", "user")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, "No generated files found for domain user")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "destroy", "widget", "user")
+		if err == nil || !strings.Contains(err.Error(), `unknown type "widget"`) {
+			t.Fatalf("expected unknown type error, got %v", err)
+		}
+	})
 }
 
-func TestGeneratorSkipExisting(t *testing.T) {
+func TestGeneratorStatus(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
 	tmpDir := chdir(t)
-	filePath := filepath.Join(tmpDir, "internal", "service", "foo.go")
 
-	// 第一次创建文件
-	_, _, err := executeCommandC(generator.GeneratorCmd, "service", "foo")
+	_, output, err := executeCommandC(generator.GeneratorCmd, "status")
 	if err != nil {
 		t.Fatalf("command failed: %v", err)
 	}
-	assertFileExists(t, filePath)
-
-	// 记录文件修改时间
-	info1, err := os.Stat(filePath)
-	if err != nil {
-		t.Fatalf("failed to stat file: %v", err)
-	}
+	assertMatches(t, output, "No generated files recorded")
 
-	// 再次执行相同命令（不带 --force），文件应被跳过且内容不变
-	_, _, err = executeCommandC(generator.GeneratorCmd, "service", "foo")
-	if err != nil {
-		t.Fatalf("command failed: %v", err)
-	}
-	info2, err := os.Stat(filePath)
-	if err != nil {
-		t.Fatalf("failed to stat file: %v", err)
+	if _, _, err := executeCommandC(generator.GeneratorCmd, "domain", "user", "name:string", "--with-test"); err != nil {
+		t.Fatalf("domain failed: %v", err)
 	}
-	if info1.ModTime() != info2.ModTime() {
-		t.Errorf("file should not be modified when skipped (modtime changed)")
+	if _, _, err := executeCommandC(generator.GeneratorCmd, "service", "user"); err != nil {
+		t.Fatalf("service failed: %v", err)
 	}
+	lockPath := filepath.Join(tmpDir, ".gouno.lock")
+	assertFileContains(t, lockPath, "internal/domain/user.go:")
+	assertFileContains(t, lockPath, "template: domain_test")
+	assertFileContains(t, lockPath, "template-set-version: "+gouno.Version)
+	assertFileMatches(t, lockPath, `output-hash: sha256:[0-9a-f]{64}`)
+	assertFileContains(t, lockPath, "- name:string")
 
-	// 带 --force 应覆盖
-	_, _, err = executeCommandC(generator.GeneratorCmd, "service", "foo", "--force")
-	if err != nil {
-		t.Fatalf("command failed: %v", err)
-	}
-	info3, err := os.Stat(filePath)
-	if err != nil {
-		t.Fatalf("failed to stat file: %v", err)
-	}
-	if info2.ModTime() == info3.ModTime() {
-		t.Errorf("file should be modified with --force (modtime unchanged)")
-	}
-}
+	t.Run("up to date", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "status")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `3 generated file\(s\): 3 up to date`)
+		if strings.Contains(output, "STATUS") {
+			t.Errorf("up-to-date files should only be listed with --all, got:\n%s", output)
+		}
+		_, output, _ = executeCommandC(generator.GeneratorCmd, "status", "--all")
+		assertMatches(t, output, `up to date\s+internal/domain/user\.go\s+default/domain@`+regexp.QuoteMeta(gouno.Version))
+	})
 
-func assertFileExists(t *testing.T, path string) {
-	t.Helper()
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		t.Errorf("expected file to exist: %s", path)
-	}
+	t.Run("modified, outdated and missing", func(t *testing.T) {
+		servicePath := filepath.Join(tmpDir, "internal", "service", "user.go")
+		f, _ := os.OpenFile(servicePath, os.O_APPEND|os.O_WRONLY, 0644)
+		f.WriteString("\n// hand-written\n")
+		f.Close()
+		os.Remove(filepath.Join(tmpDir, "internal", "domain", "user_test.go"))
+		setDir := filepath.Join(tmpDir, ".gouno", "templates", "default")
+		os.MkdirAll(setDir, 0755)
+		os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte("version: 9.0.0\n"), 0644)
+		os.WriteFile(filepath.Join(setDir, "domain.tmpl"), []byte("package domain\n\ntype {{.StructName}} struct{}\n"), 0644)
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "status")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `modified\s+internal/service/user\.go`)
+		assertMatches(t, output, `outdated\s+internal/domain/user\.go\s+default/domain@`+regexp.QuoteMeta(gouno.Version)+` \(now 9\.0\.0\)`)
+		assertMatches(t, output, `missing\s+internal/domain/user_test\.go`)
+		assertMatches(t, output, `3 generated file\(s\): 1 modified, 1 outdated, 1 missing`)
+	})
+
+	t.Run("destroy forgets files", func(t *testing.T) {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "destroy", "domain", "user", "--force"); err != nil {
+			t.Fatalf("destroy failed: %v", err)
+		}
+		content, _ := os.ReadFile(lockPath)
+		if strings.Contains(string(content), "internal/domain/") {
+			t.Errorf("destroyed files still recorded:\n%s", content)
+		}
+	})
 }
 
-func assertFileContains(t *testing.T, path, substr string) {
-	t.Helper()
-	content, err := os.ReadFile(path)
-	if err != nil {
-		t.Fatalf("failed to read file %s: %v", path, err)
+func TestGeneratorUpgrade(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := chdir(t)
+	for _, name := range []string{"user", "order"} {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "domain", name); err != nil {
+			t.Fatalf("domain failed: %v", err)
+		}
 	}
-	if !strings.Contains(string(content), substr) {
-		t.Errorf("file %s does not contain %q, got:\n%s", path, substr, string(content))
+	userPath := filepath.Join(tmpDir, "internal", "domain", "user.go")
+	orderPath := filepath.Join(tmpDir, "internal", "domain", "order.go")
+	content, _ := os.ReadFile(orderPath)
+	os.WriteFile(orderPath, []byte(strings.Replace(string(content), "package domain\n", "package domain\n\n// hand-written\n", 1)), 0644)
+
+	t.Run("up to date", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "upgrade")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `All files generated from template set "default" are up to date`)
+	})
+
+	setDir := filepath.Join(tmpDir, ".gouno", "templates", "default")
+	os.MkdirAll(setDir, 0755)
+	os.WriteFile(filepath.Join(setDir, "domain.tmpl"), []byte("package domain\n\n// {{.StructName}} is an entity.\ntype {{.StructName}} struct{}\n"), 0644)
+
+	t.Run("dry run", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "upgrade", "--dry-run")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Would upgrade 2 outdated file\(s\): 1 updated, 1 kept \(modified\)`)
+		if data, _ := os.ReadFile(userPath); strings.Contains(string(data), "is an entity") {
+			t.Error("dry run should not write files")
+		}
+	})
+
+	t.Run("updates clean files", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "upgrade")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Kept modified domain file: .*order\.go`)
+		assertFileContains(t, userPath, "// User is an entity.")
+		if data, _ := os.ReadFile(orderPath); strings.Contains(string(data), "is an entity") {
+			t.Error("modified file should be kept without --merge")
+		}
+	})
+
+	t.Run("merges modified files", func(t *testing.T) {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "upgrade", "internal/domain", "--merge"); err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, orderPath, "// hand-written")
+		assertFileContains(t, orderPath, "// Order is an entity.")
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "status")
+		if err != nil {
+			t.Fatalf("status failed: %v", err)
+		}
+		assertMatches(t, output, `2 generated file\(s\): 1 modified, 1 up to date`)
+	})
+
+	t.Run("files without recorded arguments", func(t *testing.T) {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "task", "cleanup"); err != nil {
+			t.Fatalf("task failed: %v", err)
+		}
+		// mock 与 from-openapi 生成的文件不记录参数
+		lockPath := filepath.Join(tmpDir, ".gouno.lock")
+		lock, _ := os.ReadFile(lockPath)
+		os.WriteFile(lockPath, []byte(strings.Replace(string(lock), "    args:\n      - cleanup\n", "", 1)), 0644)
+		os.WriteFile(filepath.Join(setDir, "task.tmpl"), []byte("package task\n\n// {{.StructName}}Task runs in the background.\ntype {{.StructName}}Task struct{}\n"), 0644)
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "upgrade", "internal/task")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Upgraded 1 outdated file\(s\): 1 kept \(no recorded arguments\)`)
+	})
+}
+
+func TestGeneratorUpgradeVariables(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := chdir(t)
+	setDir := filepath.Join(tmpDir, ".gouno", "templates", "default")
+	os.MkdirAll(setDir, 0755)
+	os.WriteFile(filepath.Join(setDir, "domain.tmpl"), []byte("package domain\n\n// {{.StructName}} by {{.Vars.author}}.\ntype {{.StructName}} struct{}\n"), 0644)
+	if _, _, err := executeCommandC(generator.GeneratorCmd, "domain", "user", "--var", "author=alice"); err != nil {
+		t.Fatalf("domain failed: %v", err)
+	}
+	userPath := filepath.Join(tmpDir, "internal", "domain", "user.go")
+	assertFileContains(t, filepath.Join(tmpDir, ".gouno.lock"), "author: alice")
+
+	t.Run("reuses recorded variables", func(t *testing.T) {
+		os.WriteFile(filepath.Join(setDir, "domain.tmpl"), []byte("package domain\n\n// {{.StructName}} is written by {{.Vars.author}}.\ntype {{.StructName}} struct{}\n"), 0644)
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "upgrade"); err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, userPath, "// User is written by alice.")
+	})
+
+	t.Run("--var overrides recorded variables", func(t *testing.T) {
+		os.WriteFile(filepath.Join(setDir, "domain.tmpl"), []byte("package domain\n\n// {{.StructName}} is maintained by {{.Vars.author}}.\ntype {{.StructName}} struct{}\n"), 0644)
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "upgrade", "--var", "author=bob"); err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, userPath, "// User is maintained by bob.")
+		assertFileContains(t, filepath.Join(tmpDir, ".gouno.lock"), "author: bob")
+	})
+}
+
+func TestGeneratorCrud(t *testing.T) {
+	tmpDir := chdir(t)
+
+	t.Run("requires go.mod", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "crud", "order")
+		if err == nil {
+			t.Fatal("expected error without go.mod")
+		}
+	})
+
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/shop\n"), 0644)
+
+	t.Run("generates all layers", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "crud", "order", "amount:float")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+
+		domainPath := filepath.Join(tmpDir, "internal", "domain", "order.go")
+		assertFileMatches(t, domainPath, `ID\s+int64`)
+		assertFileMatches(t, domainPath, `Amount\s+float64`)
+
+		repositoryPath := filepath.Join(tmpDir, "internal", "repository", "order.go")
+		assertFileContains(t, repositoryPath, "type OrderRepository interface")
+		assertFileContains(t, repositoryPath, "func NewMemoryOrderRepository() *MemoryOrderRepository")
+		assertFileContains(t, repositoryPath, `"example.com/shop/internal/domain"`)
+
+		servicePath := filepath.Join(tmpDir, "internal", "service", "order.go")
+		assertFileContains(t, servicePath, "func NewOrderService(repository repository.OrderRepository) *OrderService")
+
+		controllerPath := filepath.Join(tmpDir, "controller", "order.go")
+		for _, handler := range []string{"List", "Get", "Create", "Update", "Delete"} {
+			assertFileContains(t, controllerPath, "func (c *OrderController) "+handler+"(ctx *gin.Context)")
+		}
+		assertFileContains(t, controllerPath, "gouno.NewNotFoundResponse()")
+		assertFileContains(t, controllerPath, "strconv.ParseInt")
+	})
+
+	t.Run("string id", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "crud", "coupon", "id:uuid", "code:string")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		repositoryPath := filepath.Join(tmpDir, "internal", "repository", "coupon.go")
+		assertFileContains(t, repositoryPath, "items map[string]*domain.Coupon")
+		controllerPath := filepath.Join(tmpDir, "controller", "coupon.go")
+		assertFileContains(t, controllerPath, `id := ctx.Param("id")`)
+	})
+
+	t.Run("unsigned id", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "crud", "invoice", "id:uint32")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, filepath.Join(tmpDir, "controller", "invoice.go"), `strconv.ParseUint(ctx.Param("id"), 10, 32)`)
+	})
+
+	t.Run("rejects unsupported id types", func(t *testing.T) {
+		for _, id := range []string{"id:float", "id:time", "id:int64:null", "id:bool"} {
+			_, _, err := executeCommandC(generator.GeneratorCmd, "crud", "refund", id)
+			if err == nil || !strings.Contains(err.Error(), "unsupported id type") {
+				t.Errorf("crud refund %s: err = %v; want unsupported id type", id, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "domain", "refund.go")); !os.IsNotExist(err) {
+			t.Error("expected no files for a rejected id type")
+		}
+	})
+
+	t.Run("rolls back every layer on failure", func(t *testing.T) {
+		os.MkdirAll(filepath.Join(tmpDir, "router"), 0755)
+		os.WriteFile(filepath.Join(tmpDir, "router", "router.go"), []byte("package router\n\nfunc broken(\n"), 0644)
+		os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte("router:\n  file: router/router.go\n"), 0644)
+		defer os.Remove(filepath.Join(tmpDir, ".gouno.yaml"))
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "crud", "payment", "amount:float")
+		if err == nil || !strings.Contains(err.Error(), "failed to update router file") {
+			t.Fatalf("expected router error, got %v", err)
+		}
+		assertMatches(t, output, `Rolled back: .*domain/payment\.go`)
+		for _, path := range []string{
+			filepath.Join(tmpDir, "internal", "domain", "payment.go"),
+			filepath.Join(tmpDir, "internal", "repository", "payment.go"),
+			filepath.Join(tmpDir, "internal", "service", "payment.go"),
+			filepath.Join(tmpDir, "controller", "payment.go"),
+		} {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("expected %s to be rolled back, got %v", path, err)
+			}
+		}
+	})
+}
+
+// TestGeneratorCrudIDTypesCompile 为每种支持的主键类型生成 CRUD 与测试，并确认生成的项目可以编译
+func TestGeneratorCrudIDTypesCompile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go vet of the generated project in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	moduleRoot, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join(moduleRoot, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := chdir(t)
+	goMod := "module example.com/shop\n\ngo 1.23.0\n\nrequire github.com/rushairer/gouno v1.0.0\n\nreplace github.com/rushairer/gouno => " + moduleRoot + "\n"
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goMod), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "go.sum"), goSum, 0644)
+
+	for _, idType := range []string{"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "string", "text", "uuid"} {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "crud", "item_"+idType, "id:"+idType, "name:string", "--with-test", "--no-route")
+		if err != nil {
+			t.Fatalf("crud with id:%s failed: %v", idType, err)
+		}
+	}
+
+	vet := exec.Command(goBin, "vet", "./...")
+	vet.Dir = tmpDir
+	vet.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := vet.CombinedOutput(); err != nil {
+		t.Fatalf("generated project does not compile: %v\n%s", err, output)
+	}
+}
+
+func TestGeneratorApply(t *testing.T) {
+	tmpDir := chdir(t)
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/shop\n"), 0644)
+
+	t.Run("generates every resource", func(t *testing.T) {
+		os.WriteFile(filepath.Join(tmpDir, "spec.yaml"), []byte(`resources:
+  - kind: crud
+    name: order
+    fields: [amount:float]
+  - kind: suite
+    name: admin/user
+    options:
+      with-test: true
+  - kind: task
+    name: order
+  - kind: task
+    name: refund
+    options: {path: blocker/task}
+`), 0644)
+		os.WriteFile(filepath.Join(tmpDir, "blocker"), nil, 0644)
+		_, output, err := executeCommandC(generator.GeneratorCmd, "apply", "spec.yaml")
+		if err == nil || !strings.Contains(err.Error(), "1 of 4 resource(s) failed") {
+			t.Fatalf("expected failure to be reported, got %v", err)
+		}
+		assertFileMatches(t, filepath.Join(tmpDir, "internal", "domain", "order.go"), `Amount\s+float64`)
+		assertFileExists(t, filepath.Join(tmpDir, "controller", "order.go"))
+		assertFileExists(t, filepath.Join(tmpDir, "internal", "service", "admin", "user_test.go"))
+		assertFileExists(t, filepath.Join(tmpDir, "internal", "task", "order.go"))
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "domain", "order_test.go")); !os.IsNotExist(err) {
+			t.Errorf("options should only apply to their resource, got %v", err)
+		}
+		assertMatches(t, output, `Failed task refund: `)
+		assertMatches(t, output, `Applied 4 resource\(s\) from spec\.yaml: 11 created, 0 skipped, 0 overwritten, 1 failed`)
+	})
+
+	t.Run("flags apply to every resource", func(t *testing.T) {
+		os.WriteFile(filepath.Join(tmpDir, "spec.yaml"), []byte(`resources:
+  - kind: task
+    name: order
+  - kind: task
+    name: refund
+`), 0644)
+		_, output, err := executeCommandC(generator.GeneratorCmd, "apply", "spec.yaml", "--force")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `1 created, 0 skipped, 1 overwritten, 0 failed`)
+	})
+
+	t.Run("checks the spec first", func(t *testing.T) {
+		os.WriteFile(filepath.Join(tmpDir, "spec.yaml"), []byte(`resources:
+  - kind: task
+    name: payout
+  - kind: widget
+    name: x
+  - kind: crud
+    name: y
+    options: {pth: a}
+  - kind: domain
+    name: type
+  - kind: suite
+    name: invoice
+    fields: [total]
+  - kind: crud
+    name: coupon
+    fields: [id:float]
+`), 0644)
+		_, _, err := executeCommandC(generator.GeneratorCmd, "apply", "spec.yaml")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		for _, want := range []string{
+			`resources[1] (widget x): unknown kind "widget"`,
+			`resources[2] (crud y): unknown option "pth" for crud`,
+			`resources[3] (domain type): invalid name "type"`,
+			`resources[4] (suite invoice): invalid field "total"`,
+			`resources[5] (crud coupon): unsupported id type "float64"`,
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error should contain %q, got:\n%v", want, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "task", "payout.go")); !os.IsNotExist(err) {
+			t.Errorf("nothing should be generated for an invalid spec, got %v", err)
+		}
+	})
+}
+
+func TestGeneratorDryRun(t *testing.T) {
+	tmpDir := chdir(t)
+	filePath := filepath.Join(tmpDir, "internal", "service", "foo.go")
+
+	t.Run("new file", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "service", "foo", "--dry-run")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal")); !os.IsNotExist(err) {
+			t.Errorf("dry run should not create directories")
+		}
+		if !strings.Contains(output, "Would create service file: "+filePath) {
+			t.Errorf("unexpected output:\n%s", output)
+		}
+	})
+
+	t.Run("diff against existing", func(t *testing.T) {
+		os.MkdirAll(filepath.Dir(filePath), 0755)
+		os.WriteFile(filePath, []byte("package service\n\ntype FooService struct {\n\tdb string\n}\n"), 0644)
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "service", "foo", "--diff", "--force")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		for _, want := range []string{"Would overwrite service file", "--- " + filePath, "+++ " + filePath, "-\tdb string", "+func NewFooService() *FooService {"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output does not contain %q, got:\n%s", want, output)
+			}
+		}
+		assertFileContains(t, filePath, "db string")
+	})
+
+	t.Run("suite", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "suite", "bar", "--dry-run")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if strings.Count(output, "Would create") != 3 {
+			t.Errorf("expected three files reported, got:\n%s", output)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "domain")); !os.IsNotExist(err) {
+			t.Errorf("dry run should not create files")
+		}
+	})
+}
+
+func TestGeneratorMerge(t *testing.T) {
+	tmpDir := chdir(t)
+	filePath := filepath.Join(tmpDir, "internal", "domain", "user.go")
+
+	t.Run("without recorded output", func(t *testing.T) {
+		os.MkdirAll(filepath.Dir(filePath), 0755)
+		os.WriteFile(filePath, []byte("package domain\n"), 0644)
+		_, output, err := executeCommandC(generator.GeneratorCmd, "domain", "user", "--merge")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if !strings.Contains(output, "skipping merge") {
+			t.Errorf("unexpected output:\n%s", output)
+		}
+		os.Remove(filePath)
+	})
+
+	if _, _, err := executeCommandC(generator.GeneratorCmd, "domain", "user", "name:string"); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	assertFileExists(t, filepath.Join(tmpDir, ".gouno", "cache", "internal", "domain", "user.go"))
+
+	// 手工修改生成的文件
+	content, _ := os.ReadFile(filePath)
+	edited := strings.Replace(string(content), "\treturn\n}", "\tbar = d.Name\n\treturn\n}", 1)
+	os.WriteFile(filePath, []byte(edited), 0644)
+
+	// 增加字段后重新生成，手工修改应保留
+	_, output, err := executeCommandC(generator.GeneratorCmd, "domain", "user", "name:string", "age:int", "--merge")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if !strings.Contains(output, "Merged domain file") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	assertFileContains(t, filePath, "bar = d.Name")
+	assertFileMatches(t, filePath, `Age\s+int`)
+	assertFileContains(t, filePath, "func NewUser(name string, age int) *User")
+}
+
+func TestGeneratorWithTest(t *testing.T) {
+	tmpDir := chdir(t)
+
+	t.Run("flag", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "controller", "foo", "--with-test")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		testPath := filepath.Join(tmpDir, "controller", "foo_test.go")
+		assertFileContains(t, testPath, "func TestFooControllerFoo(t *testing.T)")
+		assertFileContains(t, testPath, "httptest.NewRecorder()")
+		assertFileContains(t, testPath, "var resp gouno.Response")
+	})
+
+	t.Run("config default", func(t *testing.T) {
+		os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte("with-test: true\n"), 0644)
+		defer os.Remove(filepath.Join(tmpDir, ".gouno.yaml"))
+
+		_, _, err := executeCommandC(generator.GeneratorCmd, "task", "foo")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		testPath := filepath.Join(tmpDir, "internal", "task", "foo_test.go")
+		assertFileContains(t, testPath, "cancel()")
+		assertFileContains(t, testPath, "NewFooTask().Run(ctx)")
+
+		_, _, err = executeCommandC(generator.GeneratorCmd, "service", "foo", "--with-test=false")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "service", "foo_test.go")); !os.IsNotExist(err) {
+			t.Error("--with-test=false should override the config default")
+		}
+	})
+}
+
+func TestGeneratorMock(t *testing.T) {
+	tmpDir := chdir(t)
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/shop\n"), 0644)
+
+	t.Run("repository interface", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "crud", "order", "total:float", "--no-route")
+		if err != nil {
+			t.Fatalf("crud failed: %v", err)
+		}
+		_, _, err = executeCommandC(generator.GeneratorCmd, "mock", "order")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		mockPath := filepath.Join(tmpDir, "internal", "repository", "mock", "order.go")
+		assertFileContains(t, mockPath, "// Code generated by gouno gen mock; DO NOT EDIT.")
+		assertFileContains(t, mockPath, "var _ repository.OrderRepository = (*FakeOrderRepository)(nil)")
+		assertFileContains(t, mockPath, "func (fake *FakeOrderRepository) Get(ctx context.Context, id int64) (*domain.Order, error) {")
+		assertFileMatches(t, mockPath, `GetReturns\s+FakeOrderRepositoryGetReturns`)
+		assertFileMatches(t, mockPath, `ID\s+int64`)
+	})
+
+	t.Run("service", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "mock", "order", "--kind", "service")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		mockPath := filepath.Join(tmpDir, "internal", "service", "mock", "order.go")
+		assertFileContains(t, mockPath, "type FakeOrderService struct {")
+		assertFileContains(t, mockPath, "func (fake *FakeOrderService) List(ctx context.Context) ([]*domain.Order, error) {")
+	})
+
+	t.Run("repository flag regenerates", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "repository", "user", "--mock")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		mockPath := filepath.Join(tmpDir, "internal", "repository", "mock", "user.go")
+		assertFileContains(t, mockPath, "func (fake *FakeUserRepository) Foo(ctx context.Context) (string, error) {")
+
+		f, _ := os.OpenFile(filepath.Join(tmpDir, "internal", "repository", "user.go"), os.O_APPEND|os.O_WRONLY, 0644)
+		f.WriteString("\nfunc (r *UserRepository) Tag(ctx context.Context, tags ...string) {}\n")
+		f.Close()
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "mock", "user")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if !strings.Contains(output, "Overwrote mock file") {
+			t.Errorf("expected generated fake to be overwritten without --force, got %q", output)
+		}
+		assertFileContains(t, mockPath, "func (fake *FakeUserRepository) Tag(ctx context.Context, tags ...string) {")
+		assertFileContains(t, mockPath, "stub(ctx, tags...)")
+	})
+
+	t.Run("keeps files generated by other tools", func(t *testing.T) {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "repository", "payment"); err != nil {
+			t.Fatalf("repository failed: %v", err)
+		}
+		mockPath := filepath.Join(tmpDir, "internal", "repository", "mock", "payment.go")
+		os.MkdirAll(filepath.Dir(mockPath), 0755)
+		foreign := "// Code generated by mockery; DO NOT EDIT.\n\npackage mock\n"
+		os.WriteFile(mockPath, []byte(foreign), 0644)
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "mock", "payment")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `mock file already exists, skipping: .*payment\.go`)
+		if content, _ := os.ReadFile(mockPath); string(content) != foreign {
+			t.Errorf("file generated by another tool should be kept, got:\n%s", content)
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "mock", "missing"); err == nil {
+			t.Fatal("expected error for missing type")
+		}
+	})
+}
+
+func TestGeneratorFromSQL(t *testing.T) {
+	tmpDir := chdir(t)
+	schema := `CREATE TABLE users (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	email VARCHAR(255) NOT NULL,
+	nickname VARCHAR(64),
+	created_at DATETIME NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE KEY uk_email (email)
+);
+
+CREATE TABLE categories (
+	id INT PRIMARY KEY,
+	location POINT
+);
+`
+	os.WriteFile(filepath.Join(tmpDir, "schema.sql"), []byte(schema), 0644)
+
+	_, output, err := executeCommandC(generator.GeneratorCmd, "from-sql", "schema.sql")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	domainPath := filepath.Join(tmpDir, "internal", "domain", "user.go")
+	assertFileMatches(t, domainPath, `ID\s+int64\s+`+"`json:\"id\" db:\"id,pk\"`")
+	assertFileMatches(t, domainPath, `Email\s+string\s+`+"`json:\"email\" db:\"email,unique\"`")
+	assertFileMatches(t, domainPath, `Nickname\s+\*string\s+`)
+	assertFileMatches(t, domainPath, `CreatedAt\s+time\.Time\s+`)
+	repositoryPath := filepath.Join(tmpDir, "internal", "repository", "user.go")
+	assertFileContains(t, repositoryPath, "func (r *UserRepository) ExistsByID(ctx context.Context, id int64) (exists bool, err error)")
+	assertFileContains(t, repositoryPath, "func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (exists bool, err error)")
+	assertFileContains(t, filepath.Join(tmpDir, "internal", "service", "user.go"), "func (s *UserService) Create(ctx context.Context, id int64, email string, nickname *string, createdAt time.Time) error")
+
+	assertFileMatches(t, filepath.Join(tmpDir, "internal", "domain", "category.go"), `Location\s+\*string\s+`)
+	if !strings.Contains(output, `Unsupported type "point" for column categories.location`) {
+		t.Errorf("expected unsupported type warning, got %q", output)
+	}
+
+	t.Run("table filter", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "from-sql", "schema.sql", "--table", "categories", "--dry-run")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if strings.Contains(output, "users") {
+			t.Errorf("expected only categories, got %q", output)
+		}
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "from-sql", "schema.sql", "--table", "orders"); err == nil {
+			t.Error("expected error for unknown table")
+		}
+	})
+
+	t.Run("name override", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "from-sql", "schema.sql", "--table", "categories", "--name", "categories=tag")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Generating suite tag from table categories`)
+		assertFileExists(t, filepath.Join(tmpDir, "internal", "domain", "tag.go"))
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "from-sql", "schema.sql", "--name", "orders=order"); err == nil || !strings.Contains(err.Error(), `table "orders" not found`) {
+			t.Errorf("expected error for unknown table, got %v", err)
+		}
+	})
+}
+
+func TestGeneratorFromOpenAPI(t *testing.T) {
+	tmpDir := chdir(t)
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/shop\n"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "router"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "router", "router.go"), []byte("package router\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc Register(r *gin.Engine) {\n}\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte("router:\n  file: router/router.go\n"), 0644)
+	spec := `openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+servers: [{url: /api}]
+tags:
+  - {name: pets, description: Pet operations.}
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      tags: [pets]
+      summary: Create a pet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/Pet"}
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      tags: [pets]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer, format: int64}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        id: {type: integer, format: int64}
+        name: {type: string}
+`
+	os.WriteFile(filepath.Join(tmpDir, "api.yaml"), []byte(spec), 0644)
+
+	_, _, err := executeCommandC(generator.GeneratorCmd, "from-openapi", "api.yaml")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	dtoPath := filepath.Join(tmpDir, "controller", "api_dto.go")
+	assertFileContains(t, dtoPath, "// Code generated by gouno gen from-openapi from Pets 1.0.0; DO NOT EDIT.")
+	assertFileMatches(t, dtoPath, `Name\s+string\s+`+"`json:\"name\" binding:\"required\"`")
+	assertFileMatches(t, dtoPath, `ID\s+int64\s+`+"`uri:\"id\" binding:\"required\"`")
+
+	controllerPath := filepath.Join(tmpDir, "controller", "pets.go")
+	assertFileContains(t, controllerPath, "// PetsController handles the pets operations of Pets.\n// Pet operations.")
+	assertFileContains(t, controllerPath, "// CreatePet handles POST /pets.\n// Create a pet\nfunc (c *PetsController) CreatePet(ctx *gin.Context) {")
+	assertFileContains(t, controllerPath, "if err := ctx.ShouldBindJSON(&req); err != nil {")
+	assertFileContains(t, controllerPath, `ctx.JSON(http.StatusCreated, gouno.NewResponse(http.StatusCreated, "success", resp))`)
+	assertFileContains(t, controllerPath, "if err := ctx.ShouldBindUri(&path); err != nil {")
+	assertFileContains(t, controllerPath, "ctx.JSON(http.StatusOK, gouno.NewSuccessResponse(resp))")
+
+	routerPath := filepath.Join(tmpDir, "router", "router.go")
+	assertFileContains(t, routerPath, `petsGroup := r.Group("/api/pets")`)
+	assertFileContains(t, routerPath, `petsGroup.POST("", petsController.CreatePet)`)
+	assertFileContains(t, routerPath, `petsGroup.GET("/:id", petsController.GetPet)`)
+
+	t.Run("regenerates DTOs", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "from-openapi", "api.yaml")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if !strings.Contains(output, "Overwrote dto file") || !strings.Contains(output, "controller file already exists") {
+			t.Errorf("expected DTOs to be regenerated and controllers kept, got %q", output)
+		}
+	})
+
+	t.Run("rolls back every tag on failure", func(t *testing.T) {
+		os.WriteFile(filepath.Join(tmpDir, "shop.yaml"), []byte(`openapi: 3.0.3
+info: {title: Shop, version: 1.0.0}
+paths:
+  /stores:
+    get:
+      operationId: listStores
+      tags: [stores]
+      responses:
+        "200": {description: ok}
+  /orders:
+    get:
+      operationId: listOrders
+      tags: [orders]
+      responses:
+        "200": {description: ok}
+`), 0644)
+		// 目录占据 orders 控制器的路径，使第二个标签写入失败
+		os.MkdirAll(filepath.Join(tmpDir, "controller", "orders.go"), 0755)
+		defer os.Remove(filepath.Join(tmpDir, "controller", "orders.go"))
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "from-openapi", "shop.yaml")
+		if err == nil {
+			t.Fatal("expected error for the blocked orders controller")
+		}
+		assertMatches(t, output, `Rolled back: .*controller/stores\.go`)
+		if _, err := os.Stat(filepath.Join(tmpDir, "controller", "stores.go")); !os.IsNotExist(err) {
+			t.Errorf("expected stores controller to be rolled back, got %v", err)
+		}
+		if content, _ := os.ReadFile(routerPath); strings.Contains(string(content), "storesController") {
+			t.Errorf("expected stores routes to be rolled back:\n%s", content)
+		}
+	})
+}
+
+func TestGeneratorTemplateManifest(t *testing.T) {
+	tmpDir := chdir(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	setDir := filepath.Join(home, ".gouno", "templates", "company")
+	os.MkdirAll(setDir, 0755)
+	os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte(`name: company
+version: 1.0.0
+min-gouno-version: 1.0.0
+types: [domain]
+paths:
+  domain: pkg/model
+variables:
+  - name: author
+    default: platform-team
+    pattern: ^[a-z-]+$
+`), 0644)
+	os.WriteFile(filepath.Join(setDir, "domain.tmpl"), []byte(`// Author: {{.Vars.author}}
+package domain
+
+type {{.StructName}} struct{}
+`), 0644)
+
+	t.Run("paths and variable defaults", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "domain", "order", "--template-set", "company")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, filepath.Join(tmpDir, "pkg", "model", "order.go"), "// Author: platform-team")
+	})
+
+	t.Run("variable override", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "domain", "user", "--template-set", "company", "--var", "author=billing-team")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, filepath.Join(tmpDir, "pkg", "model", "user.go"), "// Author: billing-team")
+	})
+
+	t.Run("invalid variable", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "domain", "item", "--template-set", "company", "--var", "author=Bad Name")
+		if err == nil || !strings.Contains(err.Error(), "must match") {
+			t.Errorf("expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "service", "order", "--template-set", "company")
+		if err == nil || !strings.Contains(err.Error(), `template set "company" does not support service templates`) {
+			t.Errorf("expected unsupported type error, got %v", err)
+		}
+	})
+
+	t.Run("incompatible version", func(t *testing.T) {
+		os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte("min-gouno-version: 99.0.0\n"), 0644)
+		_, _, err := executeCommandC(generator.GeneratorCmd, "domain", "order", "--template-set", "company")
+		if err == nil || !strings.Contains(err.Error(), "requires gouno >= 99.0.0") {
+			t.Errorf("expected version error, got %v", err)
+		}
+	})
+}
+
+func TestGeneratorTemplateExtends(t *testing.T) {
+	tmpDir := chdir(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	setDir := filepath.Join(home, ".gouno", "templates", "gorm")
+	os.MkdirAll(setDir, 0755)
+	os.WriteFile(filepath.Join(setDir, "repository.tmpl"), []byte("package repository\n\n// gorm\ntype {{.StructName}}Repository struct{}\n"), 0644)
+
+	t.Run("missing type without extends", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "suite", "order", "--template-set", "gorm")
+		if err == nil || !strings.Contains(err.Error(), `template set "gorm" has no domain template`) {
+			t.Errorf("expected missing template error, got %v", err)
+		}
+	})
+
+	t.Run("fallback to parent", func(t *testing.T) {
+		os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte("extends: default\n"), 0644)
+		_, output, err := executeCommandC(generator.GeneratorCmd, "suite", "order", "--template-set", "gorm")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, filepath.Join(tmpDir, "internal", "repository", "order.go"), "// gorm")
+		assertFileContains(t, filepath.Join(tmpDir, "internal", "domain", "order.go"), "func NewOrder(")
+		assertFileContains(t, filepath.Join(tmpDir, "internal", "service", "order.go"), "func NewOrderService(")
+		if !strings.Contains(output, "Using domain template from builtin: default (inherited by gorm)") {
+			t.Errorf("output should report the inherited template:\n%s", output)
+		}
+	})
+}
+
+func TestGeneratorMultiFileTemplate(t *testing.T) {
+	tmpDir := chdir(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	typeDir := filepath.Join(home, ".gouno", "templates", "team", "controller")
+	os.MkdirAll(typeDir, 0755)
+	os.WriteFile(filepath.Join(typeDir, "..", "template.yaml"), []byte("extends: default\n"), 0644)
+	files := map[string]string{
+		"{{.Snake}}.go":              "package {{.Package}}\n\ntype {{.StructName}}Controller struct{}\n",
+		"{{.Snake}}_handler.go.tmpl": "package {{.Package}}\n\nfunc (c *{{.StructName}}Controller) Get() {}\n",
+		"{{.Snake}}_dto.go":          "package {{.Package}}\n\ntype {{.StructName}}DTO struct{}\n",
+		"{{.Snake}}_handler_test.go": "package {{.Package}}\n\nimport \"testing\"\n\nfunc Test{{.StructName}}(t *testing.T) {}\n",
+		"{{.Snake}}.http":            "GET /{{.Snake}}/1\n",
+		".DS_Store":                  "ignored",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(typeDir, name), []byte(content), 0644)
+	}
+
+	_, output, err := executeCommandC(generator.GeneratorCmd, "controller", "order_item", "--template-set", "team")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	dir := filepath.Join(tmpDir, "controller")
+	assertFileContains(t, filepath.Join(dir, "order_item.go"), "type OrderItemController struct{}")
+	assertFileContains(t, filepath.Join(dir, "order_item_handler.go"), "func (c *OrderItemController) Get() {}")
+	assertFileContains(t, filepath.Join(dir, "order_item_dto.go"), "type OrderItemDTO struct{}")
+	assertFileContains(t, filepath.Join(dir, "order_item.http"), "GET /order_item/1")
+	if _, err := os.Stat(filepath.Join(dir, "order_item_handler_test.go")); !os.IsNotExist(err) {
+		t.Error("test files should only be generated with --with-test")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".DS_Store")); !os.IsNotExist(err) {
+		t.Error("hidden files should be ignored")
+	}
+	if !strings.Contains(output, "Using controller template from home: "+typeDir) {
+		t.Errorf("output should report the template directory:\n%s", output)
+	}
+
+	_, _, err = executeCommandC(generator.GeneratorCmd, "controller", "order_item", "--template-set", "team", "--with-test")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	assertFileContains(t, filepath.Join(dir, "order_item_handler_test.go"), "func TestOrderItem(t *testing.T) {}")
+	if _, err := os.Stat(filepath.Join(dir, "order_item_test.go")); !os.IsNotExist(err) {
+		t.Error("the builtin controller_test template should not be used for directory templates")
+	}
+
+	// 名称本身不能包含 ..，由渲染后的文件名越出输出目录
+	os.WriteFile(filepath.Join(typeDir, `{{printf "..%cescape" 47}}.go`), []byte("package {{.Package}}\n"), 0644)
+	_, _, err = executeCommandC(generator.GeneratorCmd, "controller", "escape", "--template-set", "team")
+	if err == nil || !strings.Contains(err.Error(), "outside the output directory") {
+		t.Errorf("expected error for a file name outside the output directory, got %v", err)
+	}
+}
+
+func TestGeneratorRegister(t *testing.T) {
+	tmpDir := chdir(t)
+	t.Setenv("HOME", t.TempDir())
+	setDir := filepath.Join(tmpDir, ".gouno", "templates", "events")
+	os.MkdirAll(setDir, 0755)
+	os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte("extends: default\nkinds:\n  notice: {}\n"), 0644)
+	os.WriteFile(filepath.Join(setDir, "notice.tmpl"), []byte("package {{.Package}}\n\ntype {{.StructName}}Notice struct{}\n"), 0644)
+
+	var calls []string
+	root := &cobra.Command{
+		Use: "gouno",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			calls = append(calls, "pre "+cmd.Name())
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			calls = append(calls, "post "+cmd.Name())
+			return nil
+		},
+	}
+	generator.Register(root)
+	t.Cleanup(func() { root.RemoveCommand(generator.GeneratorCmd) })
+
+	_, _, err := executeCommandC(root, "gen", "notice", "signup", "--template-set", "events")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	assertFileContains(t, filepath.Join(tmpDir, "internal", "notice", "signup.go"), "type SignupNotice struct{}")
+	if got, want := strings.Join(calls, ", "), "pre notice, post notice"; got != want {
+		t.Errorf("host hooks = %q; want %q", got, want)
+	}
+}
+
+func TestGeneratorAddCommand(t *testing.T) {
+	tmpDir := chdir(t)
+	t.Setenv("HOME", t.TempDir())
+	setDir := filepath.Join(tmpDir, ".gouno", "templates", "alerts")
+	os.MkdirAll(setDir, 0755)
+	os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte("extends: default\nkinds:\n  alert: {}\n"), 0644)
+	os.WriteFile(filepath.Join(setDir, "alert.tmpl"), []byte("package {{.Package}}\n\ntype {{.StructName}}Alert struct{}\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "spec.yaml"), []byte("template-set: alerts\nresources:\n  - kind: alert\n    name: outage\n"), 0644)
+
+	// 未调用 Register 的宿主 CLI 仍可以在 apply 中使用自定义类型
+	root := &cobra.Command{Use: "gouno"}
+	root.AddCommand(generator.GeneratorCmd)
+	t.Cleanup(func() { root.RemoveCommand(generator.GeneratorCmd) })
+
+	_, _, err := executeCommandC(root, "gen", "apply", "spec.yaml")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	assertFileContains(t, filepath.Join(tmpDir, "internal", "alert", "outage.go"), "type OutageAlert struct{}")
+}
+
+func TestGeneratorTemplateKinds(t *testing.T) {
+	tmpDir := chdir(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/shop\n\ngo 1.23\n"), 0644)
+
+	setDir := filepath.Join(home, ".gouno", "templates", "events")
+	os.MkdirAll(setDir, 0755)
+	os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte(`extends: default
+kinds:
+  event:
+    aliases: [ev]
+    short: Generate a domain event
+  consumer:
+    path: internal/mq/consumer
+`), 0644)
+	os.WriteFile(filepath.Join(setDir, "event.tmpl"), []byte("package {{.Package}}\n\n// {{index .Packages \"consumer\"}}\ntype {{.StructName}}Event struct{}\n"), 0644)
+	os.WriteFile(filepath.Join(setDir, "consumer.tmpl"), []byte("package {{.Package}}\n\ntype {{.StructName}}Consumer struct{}\n"), 0644)
+	generator.RegisterKindCommands()
+
+	t.Run("alias and default path", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "ev", "order_placed", "--template-set", "events")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		path := filepath.Join(tmpDir, "internal", "event", "order_placed.go")
+		assertFileContains(t, path, "package event")
+		assertFileContains(t, path, "type OrderPlacedEvent struct{}")
+		assertFileContains(t, path, "// example.com/shop/internal/mq/consumer")
+	})
+
+	t.Run("declared path", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "consumer", "order", "--template-set", "events")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, filepath.Join(tmpDir, "internal", "mq", "consumer", "order.go"), "package consumer")
+	})
+
+	t.Run("builtin kinds still inherited", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "task", "sync", "--template-set", "events")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileExists(t, filepath.Join(tmpDir, "internal", "task", "sync.go"))
+	})
+
+	t.Run("set without the kind", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "event", "user_created")
+		if err == nil || !strings.Contains(err.Error(), `template set "default" does not declare the event kind (declared by: events)`) {
+			t.Errorf("expected undeclared kind error, got %v", err)
+		}
+	})
+}
+
+func TestGeneratorTemplateLayers(t *testing.T) {
+	tmpDir := chdir(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	writeTemplate := func(root, typeName, marker string) {
+		dir := filepath.Join(root, "acme")
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, typeName+".tmpl"), []byte("package "+typeName+"\n\n// "+marker+"\ntype {{.StructName}} struct{}\n"), 0644)
+	}
+	for _, typeName := range []string{"domain", "service", "task"} {
+		writeTemplate(filepath.Join(home, ".gouno", "templates"), typeName, "home")
+	}
+	for _, typeName := range []string{"domain", "service"} {
+		writeTemplate(filepath.Join(tmpDir, "shared"), typeName, "shared")
+	}
+	writeTemplate(filepath.Join(tmpDir, ".gouno", "templates"), "domain", "project")
+	os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte("template-set: acme\ntemplate-paths: [shared]\n"), 0644)
+
+	tests := []struct {
+		typeName string
+		path     string
+		layer    string
+	}{
+		{"domain", filepath.Join("internal", "domain", "foo.go"), "project"},
+		{"service", filepath.Join("internal", "service", "foo.go"), "shared"},
+		{"task", filepath.Join("internal", "task", "foo.go"), "home"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			_, output, err := executeCommandC(generator.GeneratorCmd, tt.typeName, "foo")
+			if err != nil {
+				t.Fatalf("command failed: %v", err)
+			}
+			assertFileContains(t, filepath.Join(tmpDir, tt.path), "// "+tt.layer)
+			if got := strings.Count(output, "Using "+tt.typeName+" template from"); got != 1 {
+				t.Errorf("template source should be reported once, got %d:\n%s", got, output)
+			}
+		})
+	}
+
+	_, output, err := executeCommandC(generator.GeneratorCmd, "template", "list")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	assertMatches(t, output, `\*\s+acme\s+-\s+1\s+project`)
+}
+
+func TestGeneratorTemplate(t *testing.T) {
+	tmpDir := chdir(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	templates := filepath.Join(home, ".gouno", "templates")
+
+	src := filepath.Join(tmpDir, "src", "acme")
+	os.MkdirAll(src, 0755)
+	os.WriteFile(filepath.Join(src, "template.yaml"), []byte("name: acme\nversion: 0.2.0\ndescription: Acme services\n"), 0644)
+	os.WriteFile(filepath.Join(src, "service.tmpl"), []byte("package service\n\ntype {{.StructName}}Service struct{}\n"), 0644)
+
+	t.Run("install directory", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "template", "install", src)
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if !strings.Contains(output, "Installed template set acme (service)") {
+			t.Errorf("unexpected output: %s", output)
+		}
+		assertFileExists(t, filepath.Join(templates, "acme", "service.tmpl"))
+
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "template", "install", src); err == nil || !strings.Contains(err.Error(), "already installed") {
+			t.Errorf("expected already installed error, got %v", err)
+		}
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "template", "install", src, "--force"); err != nil {
+			t.Errorf("--force should replace the set: %v", err)
+		}
+	})
+
+	t.Run("install archives", func(t *testing.T) {
+		tgz := filepath.Join(tmpDir, "acme.tar.gz")
+		writeArchive(t, tgz, map[string]string{
+			"acme/template.yaml": "version: 0.3.0\nhooks:\n  - run: make lint\n    types: [service]\n",
+			"acme/service.tmpl":  "package service\n",
+		})
+		_, output, err := executeCommandC(generator.GeneratorCmd, "template", "install", tgz, "acme-tgz")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `declares hooks, which only run once listed in trusted-hooks .*\n  make lint \(types: service\)`)
+		assertFileContains(t, filepath.Join(templates, "acme-tgz", "template.yaml"), "version: 0.3.0")
+
+		zipPath := filepath.Join(tmpDir, "acme-zip.zip")
+		writeArchive(t, zipPath, map[string]string{"domain.tmpl": "package domain\n"})
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "template", "install", zipPath); err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileExists(t, filepath.Join(templates, "acme-zip", "domain.tmpl"))
+
+		evil := filepath.Join(tmpDir, "evil.tar.gz")
+		writeArchive(t, evil, map[string]string{"../evil.tmpl": "package evil\n"})
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "template", "install", evil); err == nil || !strings.Contains(err.Error(), "escapes the template directory") {
+			t.Errorf("expected path traversal error, got %v", err)
+		}
+	})
+
+	t.Run("list and show", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "template", "list")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		for _, want := range []string{"default", "acme", "0.2.0", "Acme services", "acme-tgz", "acme-zip"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("list output should contain %q:\n%s", want, output)
+			}
+		}
+
+		_, output, err = executeCommandC(generator.GeneratorCmd, "template", "show", "acme")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if !strings.Contains(output, "Version:     0.2.0") || !strings.Contains(output, "Templates:   service") {
+			t.Errorf("unexpected show output:\n%s", output)
+		}
+
+		_, output, err = executeCommandC(generator.GeneratorCmd, "template", "show", "acme-tgz")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Hooks:\n  make lint \(types: service\)`)
+
+		_, output, err = executeCommandC(generator.GeneratorCmd, "template", "show", "default", "task")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if !strings.Contains(output, "func (t *{{.StructName}}Task) Run") {
+			t.Errorf("show should print the builtin template:\n%s", output)
+		}
+	})
+
+	t.Run("eject", func(t *testing.T) {
+		_, _, err := executeCommandC(generator.GeneratorCmd, "template", "eject", "domain", "--template-set", "acme")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, filepath.Join(templates, "acme", "domain.tmpl"), "func New{{.StructName}}(")
+
+		_, output, err := executeCommandC(generator.GeneratorCmd, "template", "eject", "domain", "--template-set", "acme")
+		if err != nil || !strings.Contains(output, "Skipped domain template") {
+			t.Errorf("existing templates should be skipped, got %v: %s", err, output)
+		}
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "template", "eject", "widget"); err == nil {
+			t.Error("expected error for unknown template type")
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "template", "remove", "acme-zip"); err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(templates, "acme-zip")); !os.IsNotExist(err) {
+			t.Error("template set should be removed")
+		}
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "template", "remove", "acme-zip"); err == nil {
+			t.Error("expected error for a set that is not installed")
+		}
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "template", "remove", "default"); err == nil {
+			t.Error("expected error for the builtin set")
+		}
+	})
+}
+
+// writeArchive 按扩展名创建 .tar.gz 或 .zip 归档
+func writeArchive(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(path, ".zip") {
+		zw := zip.NewWriter(f)
+		for name, content := range files {
+			w, _ := zw.Create(name)
+			w.Write([]byte(content))
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGeneratorAliases(t *testing.T) {
+	tmpDir := chdir(t)
+
+	// 每个别名对应的实际路径（与 defaultPath 一致）
+	paths := map[string]string{
+		"c": filepath.Join("controller", "foo.go"),
+		"d": filepath.Join("internal", "domain", "foo.go"),
+		"r": filepath.Join("internal", "repository", "foo.go"),
+		"s": filepath.Join("internal", "service", "foo.go"),
+		"t": filepath.Join("internal", "task", "foo.go"),
+	}
+	pkgNames := map[string]string{
+		"c": "package controller",
+		"d": "package domain",
+		"r": "package repository",
+		"s": "package service",
+		"t": "package task",
+	}
+
+	for alias, relPath := range paths {
+		t.Run(alias, func(t *testing.T) {
+			_, _, err := executeCommandC(generator.GeneratorCmd, alias, "foo")
+			if err != nil {
+				t.Fatalf("command %q failed: %v", alias, err)
+			}
+			assertFileContains(t, filepath.Join(tmpDir, relPath), pkgNames[alias])
+		})
+	}
+}
+
+func TestGeneratorNoArgs(t *testing.T) {
+	commands := []string{"service", "domain", "repository", "task", "controller"}
+	for _, name := range commands {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := executeCommandC(generator.GeneratorCmd, name)
+			if err == nil {
+				t.Errorf("expected error for %q with no args, got nil", name)
+			}
+		})
+	}
+}
+
+func TestGeneratorCamelCaseConversion(t *testing.T) {
+	tmpDir := chdir(t)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"foo_bar", "FooBar"},
+		{"my_service", "MyService"},
+		{"simple", "Simple"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, _, err := executeCommandC(generator.GeneratorCmd, "service", tt.input)
+			if err != nil {
+				t.Fatalf("command failed: %v", err)
+			}
+			filePath := filepath.Join(tmpDir, "internal", "service", tt.input+".go")
+			assertFileContains(t, filePath, tt.expected)
+		})
+	}
+}
+
+func TestGeneratorSkipExisting(t *testing.T) {
+	tmpDir := chdir(t)
+	filePath := filepath.Join(tmpDir, "internal", "service", "foo.go")
+
+	// 第一次创建文件
+	_, _, err := executeCommandC(generator.GeneratorCmd, "service", "foo")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	assertFileExists(t, filePath)
+
+	// 记录文件修改时间
+	info1, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+
+	// 再次执行相同命令（不带 --force），文件应被跳过且内容不变
+	_, _, err = executeCommandC(generator.GeneratorCmd, "service", "foo")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	info2, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+	if info1.ModTime() != info2.ModTime() {
+		t.Errorf("file should not be modified when skipped (modtime changed)")
+	}
+
+	// 带 --force 应覆盖
+	_, _, err = executeCommandC(generator.GeneratorCmd, "service", "foo", "--force")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	info3, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+	if info2.ModTime() == info3.ModTime() {
+		t.Errorf("file should be modified with --force (modtime unchanged)")
+	}
+}
+
+func TestGeneratorHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test use sh")
+	}
+	t.Setenv("HOME", t.TempDir())
+	tmpDir := chdir(t)
+	os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), []byte(`hooks:
+  - echo "$GOUNO_COMMAND $GOUNO_NAME $GOUNO_TEMPLATE_SET" >> hooks.log
+  - run: echo "$GOUNO_GENERATED_FILES" >> hooks.log
+    types: [task]
+`), 0644)
+	setDir := filepath.Join(tmpDir, ".gouno", "templates", "default")
+	os.MkdirAll(setDir, 0755)
+	os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte("hooks:\n  - echo manifest >> hooks.log\n"), 0644)
+	logPath := filepath.Join(tmpDir, "hooks.log")
+
+	t.Run("skips untrusted manifest hooks", func(t *testing.T) {
+		_, output, err := executeCommandC(generator.GeneratorCmd, "domain", "account")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, `Skipped untrusted hook from template set default: echo manifest >> hooks.log \(add it to trusted-hooks`)
+		content, _ := os.ReadFile(logPath)
+		if got, want := string(content), "domain account default\n"; got != want {
+			t.Errorf("hooks.log = %q; want %q", got, want)
+		}
+	})
+
+	config, _ := os.ReadFile(filepath.Join(tmpDir, ".gouno.yaml"))
+	os.WriteFile(filepath.Join(tmpDir, ".gouno.yaml"), append(config, "trusted-hooks:\n  - echo manifest >> hooks.log\n"...), 0644)
+
+	t.Run("runs trusted manifest then project hooks", func(t *testing.T) {
+		os.Remove(logPath)
+		_, output, err := executeCommandC(generator.GeneratorCmd, "domain", "user")
+		if err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertMatches(t, output, "Running hook: echo manifest")
+		content, _ := os.ReadFile(logPath)
+		if got, want := string(content), "manifest\ndomain user default\n"; got != want {
+			t.Errorf("hooks.log = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("passes generated files", func(t *testing.T) {
+		os.Remove(logPath)
+		if _, _, err := executeCommandC(generator.GeneratorCmd, "task", "sync"); err != nil {
+			t.Fatalf("command failed: %v", err)
+		}
+		assertFileContains(t, logPath, filepath.Join(tmpDir, "internal", "task", "sync.go"))
+	})
+
+	t.Run("skipped without writes or with --no-hooks", func(t *testing.T) {
+		os.Remove(logPath)
+		for _, args := range [][]string{
+			{"domain", "user"},
+			{"domain", "order", "--dry-run"},
+			{"domain", "order", "--no-hooks"},
+		} {
+			if _, _, err := executeCommandC(generator.GeneratorCmd, args...); err != nil {
+				t.Fatalf("%v failed: %v", args, err)
+			}
+		}
+		if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+			t.Errorf("expected no hooks to run, got %v", err)
+		}
+	})
+
+	t.Run("failing hook", func(t *testing.T) {
+		os.WriteFile(filepath.Join(setDir, "template.yaml"), []byte("hooks:\n  - exit 3\n"), 0644)
+		_, _, err := executeCommandC(generator.GeneratorCmd, "domain", "product", "--trust-hooks")
+		if err == nil || !strings.Contains(err.Error(), `hook "exit 3" failed`) {
+			t.Fatalf("expected hook failure, got %v", err)
+		}
+	})
+}
+
+func assertFileExists(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		t.Errorf("expected file to exist: %s", path)
+	}
+}
+
+func assertFileContains(t *testing.T, path, substr string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file %s: %v", path, err)
+	}
+	if !strings.Contains(string(content), substr) {
+		t.Errorf("file %s does not contain %q, got:\n%s", path, substr, string(content))
+	}
+}
+
+func assertMatches(t *testing.T, s, pattern string) {
+	t.Helper()
+	if !regexp.MustCompile(pattern).MatchString(s) {
+		t.Errorf("output does not match %q:\n%s", pattern, s)
+	}
+}
+
+func assertFileMatches(t *testing.T, path, pattern string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file %s: %v", path, err)
+	}
+	if !regexp.MustCompile(pattern).Match(content) {
+		t.Errorf("file %s does not match %q, got:\n%s", path, pattern, string(content))
 	}
 }
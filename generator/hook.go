@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Hook 是生成完成后在项目根目录执行的命令，可在 .gouno.yaml 与模板集清单中声明
+//
+//	hooks:
+//	  - go mod tidy
+//	  - run: ./scripts/lint.sh
+//	    types: [controller]
+//
+// 命令通过 sh -c（Windows 下为 cmd /C）执行，并可读取以下环境变量：
+// GOUNO_GENERATED_FILES（本次写入的文件，每行一个绝对路径）、GOUNO_COMMAND、GOUNO_NAME 与 GOUNO_TEMPLATE_SET
+type Hook struct {
+	Run   string   `yaml:"run"`
+	Types []string `yaml:"types"` // 只在写入了这些类型的文件时执行，为空时总是执行
+}
+
+// UnmarshalYAML 支持 "go mod tidy" 与 {run: ..., types: [...]} 两种写法
+func (h *Hook) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&h.Run)
+	}
+	type plain Hook
+	return node.Decode((*plain)(h))
+}
+
+// writtenFile 是本次命令实际写入的文件
+type writtenFile struct {
+	typeName string
+	path     string
+}
+
+// recordWritten 记录写入的文件，供生成完成后的钩子使用
+func recordWritten(typeName, path string) {
+	if !slices.ContainsFunc(currentSession.written, func(f writtenFile) bool { return f.path == path }) {
+		currentSession.written = append(currentSession.written, writtenFile{typeName, path})
+	}
+}
+
+// runHooks 在生成命令成功写入文件后依次执行模板集清单与 .gouno.yaml 中声明的钩子，
+// 未写入任何文件（如 --dry-run 或文件均被跳过）或指定 --no-hooks 时不执行；任一钩子失败即停止
+// 模板集清单中的钩子来自第三方，只有列在 .gouno.yaml 的 trusted-hooks 中或指定 --trust-hooks 时才执行
+func runHooks(cmd *cobra.Command, args []string) error {
+	if len(currentSession.written) == 0 {
+		return nil
+	}
+	if noHooks, _ := cmd.Flags().GetBool("no-hooks"); noHooks {
+		return nil
+	}
+	templateSet := resolveTemplateSet(cmd)
+	manifest, err := loadManifest(templateSet)
+	if err != nil {
+		return err
+	}
+	cfg := loadProjectConfig()
+	var hooks []Hook
+	if manifest != nil {
+		trustAll, _ := cmd.Flags().GetBool("trust-hooks")
+		for _, hook := range manifest.Hooks {
+			if !hook.matches(currentSession.written) {
+				continue
+			}
+			if !trustAll && (cfg == nil || !slices.Contains(cfg.TrustedHooks, hook.Run)) {
+				cmd.Printf("Skipped untrusted hook from template set %s: %s (add it to trusted-hooks in %s or use --trust-hooks)\n",
+					templateSet, hook.Run, configFileName)
+				continue
+			}
+			hooks = append(hooks, hook)
+		}
+	}
+	if cfg != nil {
+		hooks = append(hooks, cfg.Hooks...)
+	}
+
+	var paths []string
+	for _, file := range currentSession.written {
+		paths = append(paths, file.path)
+	}
+	env := append(os.Environ(),
+		"GOUNO_GENERATED_FILES="+strings.Join(paths, "\n"),
+		"GOUNO_COMMAND="+cmd.Name(),
+		"GOUNO_TEMPLATE_SET="+templateSet,
+	)
+	if len(args) > 0 {
+		env = append(env, "GOUNO_NAME="+args[0])
+	}
+
+	for _, hook := range hooks {
+		if !hook.matches(currentSession.written) {
+			continue
+		}
+		if strings.TrimSpace(hook.Run) == "" {
+			return errors.New("hook has no command to run")
+		}
+		cmd.Printf("Running hook: %s\n", hook.Run)
+		c := hookCommand(hook.Run)
+		c.Env = env
+		c.Stdout = cmd.OutOrStdout()
+		c.Stderr = cmd.ErrOrStderr()
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("hook %q failed: %w (use --no-hooks to skip hooks)", hook.Run, err)
+		}
+	}
+	return nil
+}
+
+// String 返回钩子的命令及其限定的类型，如 go vet ./... (types: controller)
+func (h Hook) String() string {
+	if len(h.Types) == 0 {
+		return h.Run
+	}
+	return fmt.Sprintf("%s (types: %s)", h.Run, strings.Join(h.Types, ", "))
+}
+
+// matches 判断钩子是否应在写入这些文件后执行
+func (h Hook) matches(files []writtenFile) bool {
+	if len(h.Types) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(files, func(f writtenFile) bool { return slices.Contains(h.Types, f.typeName) })
+}
+
+// hookCommand 返回通过系统 shell 执行钩子的命令
+func hookCommand(run string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", run)
+	}
+	return exec.Command("sh", "-c", run)
+}
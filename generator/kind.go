@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// kindAnnotation 标记由模板集自定义类型动态注册的子命令，值为类型名称
+const kindAnnotation = "gouno:kind"
+
+// kindNamePattern 是自定义类型名称的格式，名称同时用作子命令、模板文件与默认目录名
+var kindNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// defaultKindPath 返回自定义类型未声明 path 时的默认目录
+func defaultKindPath(name string) string {
+	return filepath.Join("internal", name)
+}
+
+// isBuiltinCommand 判断名称是否为内置子命令的名称或别名
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, c := range GeneratorCmd.Commands() {
+		if _, ok := c.Annotations[kindAnnotation]; !ok && (c.Name() == name || c.HasAlias(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// registerKindCommands 为查找链中各模板集声明的自定义类型注册 gouno gen <kind> 子命令
+// 多个模板集声明同名类型时只注册一次，执行时使用当前模板集（--template-set > .gouno.yaml）中的声明；
+// 清单无效的模板集被忽略，其错误在使用该模板集时报告
+func registerKindCommands() {
+	sets, err := availableTemplateSets()
+	if err != nil {
+		return
+	}
+	for _, set := range sets {
+		manifest, err := loadManifest(set.Name)
+		if err != nil || manifest == nil {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(manifest.Kinds)) {
+			if commandTaken(name) {
+				continue
+			}
+			GeneratorCmd.AddCommand(newKindCommand(name, manifest.Kinds[name]))
+		}
+	}
+}
+
+// commandTaken 判断名称是否已被某个子命令的名称或别名占用
+func commandTaken(name string) bool {
+	return slices.ContainsFunc(GeneratorCmd.Commands(), func(c *cobra.Command) bool {
+		return c.Name() == name || c.HasAlias(name)
+	})
+}
+
+// newKindCommand 创建自定义类型的子命令，参数与 flag 与内置的 domain 等子命令相同
+func newKindCommand(name string, kind TemplateKind) *cobra.Command {
+	var aliases []string
+	for _, alias := range kind.Aliases {
+		if !commandTaken(alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	path := cmp.Or(kind.Path, defaultKindPath(name))
+
+	cmd := &cobra.Command{
+		Use:                   name + " [name] [field:type[:option]...]",
+		Short:                 cmp.Or(kind.Short, "Generate "+name),
+		Aliases:               aliases,
+		Annotations:           map[string]string{kindAnnotation: name},
+		Args:                  cobra.MinimumNArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generateKind(cmd, args, name)
+		},
+	}
+	cmd.Flags().StringP("path", "p", path, "path to "+name)
+	cmd.Flags().BoolP("force", "f", false, "force overwrite")
+	cmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(cmd)
+	return cmd
+}
+
+// generateKind 使用当前模板集中的 <kind>.tmpl 生成自定义类型的文件
+func generateKind(cmd *cobra.Command, args []string, name string) error {
+	templateSet := resolveTemplateSet(cmd)
+	manifest, err := loadManifest(templateSet)
+	if err != nil {
+		return err
+	}
+	if _, ok := manifest.kind(name); !ok {
+		return fmt.Errorf("template set %q does not declare the %s kind (declared by: %s)",
+			templateSet, name, strings.Join(kindDeclarers(name), ", "))
+	}
+	return generateFile(cmd, args, name, defaultKindPath(name))
+}
+
+// kindDeclarers 返回声明了指定自定义类型的模板集
+func kindDeclarers(name string) []string {
+	sets, _ := availableTemplateSets()
+	var names []string
+	for _, set := range sets {
+		if manifest, err := loadManifest(set.Name); err == nil {
+			if _, ok := manifest.kind(name); ok {
+				names = append(names, set.Name)
+			}
+		}
+	}
+	return names
+}
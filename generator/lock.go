@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rushairer/gouno"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// lockFileName 是记录生成文件来源的锁文件，位于项目根目录
+const lockFileName = ".gouno.lock"
+
+// lockHeader 写在锁文件开头
+const lockHeader = "# Generated by gouno: the template each generated file was rendered from.\n# Commit this file; it is used by gouno gen status and gouno gen upgrade.\n"
+
+// generationLock 是 .gouno.lock 的内容，以相对于项目根目录的文件路径为键
+type generationLock struct {
+	Files map[string]*lockEntry `yaml:"files"`
+}
+
+// lockEntry 记录一个生成文件的模板来源与生成时的内容摘要
+type lockEntry struct {
+	Type               string   `yaml:"type"`
+	Template           string   `yaml:"template"`                // 模板名称，如 controller、crud_service、domain_test
+	TemplateFile       string   `yaml:"template-file,omitempty"` // 目录模板中的文件名，单文件模板为空
+	TemplateSet        string   `yaml:"template-set"`
+	TemplateSetVersion string   `yaml:"template-set-version,omitempty"`
+	TemplateHash       string   `yaml:"template-hash"`
+	OutputHash         string   `yaml:"output-hash"`    // 生成（合并前）的内容摘要
+	Args               []string `yaml:"args,omitempty"` // 生成时的名称与字段定义，用于重新渲染
+	Dir                string   `yaml:"dir,omitempty"`  // 生成目录，与文件所在目录不同时记录（目录模板中的子目录）
+
+	Vars map[string]string `yaml:"vars,omitempty"` // 生成时模板集变量的取值，升级时重新使用
+}
+
+// lockPath 返回项目的锁文件路径
+func lockPath() (string, error) {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	return filepath.Join(projectRoot, lockFileName), nil
+}
+
+// loadLock 读取项目的锁文件，不存在时返回空的锁
+func loadLock() (*generationLock, error) {
+	path, err := lockPath()
+	if err != nil {
+		return nil, err
+	}
+	lock := &generationLock{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		lock.Files = make(map[string]*lockEntry)
+		return lock, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", lockFileName, err)
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", lockFileName, err)
+	}
+	if lock.Files == nil {
+		lock.Files = make(map[string]*lockEntry)
+	}
+	return lock, nil
+}
+
+// save 写入锁文件，所有记录都被删除时删除锁文件
+func (l *generationLock) save() error {
+	path, err := lockPath()
+	if err != nil {
+		return err
+	}
+	currentSession.tx.recordFile(path)
+	if len(l.Files) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", lockFileName, err)
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString(lockHeader)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(l); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", lockFileName, err)
+	}
+	return nil
+}
+
+// lockKey 返回文件在锁中的键（相对于项目根目录的 / 分隔路径），文件不在项目目录内时返回空字符串
+func lockKey(filePath string) string {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(projectRoot, filePath)
+	if err != nil || !filepath.IsLocal(rel) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// recordLock 在生成文件实际写入后记录其模板来源，跳过的文件与 --dry-run 不记录
+// templateFile 为目录模板中的文件名，tmpl 为渲染该文件所用的模板内容
+func recordLock(cmd *cobra.Command, file *generatedFile, templateName, templateFile, tmpl, dir string) error {
+	if isDryRun(cmd) || file.Action == actionSkipped {
+		return nil
+	}
+	key := lockKey(file.Path)
+	if key == "" {
+		return nil
+	}
+	manifest, err := loadManifest(file.Data.TemplateSet)
+	if err != nil {
+		return err
+	}
+	lock, err := loadLock()
+	if err != nil {
+		return err
+	}
+	entry := &lockEntry{
+		Type:               file.TypeName,
+		Template:           templateName,
+		TemplateFile:       templateFile,
+		TemplateSet:        file.Data.TemplateSet,
+		TemplateSetVersion: templateSetVersion(file.Data.TemplateSet, manifest),
+		TemplateHash:       contentHash([]byte(tmpl)),
+		OutputHash:         contentHash([]byte(file.Content)),
+		Args:               file.Data.args,
+		Vars:               file.Data.Vars,
+	}
+	if dir != filepath.Dir(file.Path) {
+		entry.Dir = lockKey(dir)
+	}
+	lock.Files[key] = entry
+	return lock.save()
+}
+
+// forgetLock 删除文件在锁中的记录
+func forgetLock(filePath string) error {
+	key := lockKey(filePath)
+	lock, err := loadLock()
+	if err != nil || key == "" || lock.Files[key] == nil {
+		return err
+	}
+	delete(lock.Files, key)
+	return lock.save()
+}
+
+// templateSetVersion 返回模板集清单中的版本，内置的 default 模板集随 gouno 版本发布
+func templateSetVersion(templateSet string, manifest *TemplateManifest) string {
+	if manifest != nil && manifest.Version != "" {
+		return manifest.Version
+	}
+	if templateSet == "default" {
+		return gouno.Version
+	}
+	return ""
+}
+
+// templateLabel 返回锁记录的模板描述，如 default/controller@1.0.0
+func (e *lockEntry) templateLabel() string {
+	label := e.TemplateSet + "/" + cmp.Or(e.Template, e.Type)
+	if e.TemplateFile != "" {
+		label += "/" + e.TemplateFile
+	}
+	if e.TemplateSetVersion != "" {
+		label += "@" + e.TemplateSetVersion
+	}
+	return label
+}
@@ -0,0 +1,458 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/rushairer/gouno"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFileName 是模板集目录中清单文件的文件名
+const manifestFileName = "template.yaml"
+
+// TemplateManifest 模板集清单，位于模板集目录下的 template.yaml（如 ~/.gouno/templates/<set>/template.yaml）
+//
+//	name: company
+//	version: 1.2.0
+//	extends: default
+//	description: Company service templates
+//	min-gouno-version: 1.0.0
+//	types: [domain, repository, service, controller]
+//	paths:
+//	  controller: internal/handler
+//	kinds:
+//	  event:
+//	    path: internal/event
+//	    aliases: [ev]
+//	variables:
+//	  - name: author
+//	    prompt: Author name
+//	    default: platform-team
+//	    pattern: ^[a-z-]+$
+//	hooks:
+//	  - go mod tidy
+type TemplateManifest struct {
+	Name            string             `yaml:"name"`
+	Version         string             `yaml:"version"`
+	Description     string             `yaml:"description"`
+	MinGounoVersion string             `yaml:"min-gouno-version"` // 要求的最低 gouno 版本
+	Types           []string           `yaml:"types"`             // 支持的生成类型，为空时不限制
+	Paths           map[string]string  `yaml:"paths"`             // 各类型的默认生成目录，相对于项目根目录
+	Variables       []TemplateVariable `yaml:"variables"`         // 模板中通过 {{.Vars.<name>}} 引用的变量
+	Extends         string             `yaml:"extends"`           // 父模板集，缺少的模板、类型、目录与变量从父模板集继承
+
+	// Kinds 是模板集新增的生成类型，每个类型使用 <kind>.tmpl 模板并注册为 gouno gen <kind> 子命令
+	Kinds map[string]TemplateKind `yaml:"kinds"`
+
+	// Hooks 在使用本模板集的生成命令写入文件后执行，未声明时继承父模板集的钩子，见 Hook
+	Hooks []Hook `yaml:"hooks"`
+
+	chain []string // 从本模板集到最顶层父模板集的继承链，由 loadManifest 填充
+}
+
+// TemplateKind 模板集声明的自定义生成类型
+type TemplateKind struct {
+	Path    string   `yaml:"path"`    // 默认生成目录，相对于项目根目录，未声明时为 internal/<kind>
+	Aliases []string `yaml:"aliases"` // 子命令别名
+	Short   string   `yaml:"short"`   // 子命令说明，未声明时为 "Generate <kind>"
+}
+
+// TemplateVariable 模板集声明的变量
+// 取值优先级：--var name=value > .gouno.yaml 中的 variables > 交互输入 > default
+type TemplateVariable struct {
+	Name     string   `yaml:"name"`
+	Prompt   string   `yaml:"prompt"`   // 交互输入时的提示，为空时使用 name
+	Default  string   `yaml:"default"`  // 默认值
+	Required bool     `yaml:"required"` // 是否不允许为空
+	Pattern  string   `yaml:"pattern"`  // 取值需匹配的正则表达式
+	Choices  []string `yaml:"choices"`  // 可选值列表
+}
+
+// loadManifest 沿模板查找链加载模板集的清单文件并检查与当前 gouno 版本的兼容性，模板集没有清单时返回 nil
+// 声明了 extends 的清单会与父模板集的清单合并，见 inherit
+func loadManifest(templateSet string) (*TemplateManifest, error) {
+	return loadManifestChain(templateSet, nil)
+}
+
+// loadManifestChain 加载模板集清单并递归合并父模板集，chain 为已经过的子模板集，用于检测循环继承
+func loadManifestChain(templateSet string, chain []string) (*TemplateManifest, error) {
+	if slices.Contains(chain, templateSet) {
+		return nil, fmt.Errorf("template set %q has an extends cycle: %s", chain[0], strings.Join(append(chain, templateSet), " -> "))
+	}
+	path, _, ok := locateTemplateFile(templateSet, manifestFileName)
+	if !ok {
+		return nil, nil
+	}
+	manifest, err := readManifest(filepath.Dir(path), templateSet)
+	if err != nil || manifest.Extends == "" {
+		return manifest, err
+	}
+
+	parent, err := loadManifestChain(manifest.Extends, append(chain, templateSet))
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil && manifest.Extends != "default" {
+		if _, found, err := findTemplateSet(manifest.Extends); err != nil || !found {
+			return nil, fmt.Errorf("template set %q extends unknown template set %q", templateSet, manifest.Extends)
+		}
+	}
+	manifest.inherit(parent)
+	return manifest, nil
+}
+
+// readManifest 读取指定目录下的清单文件，目录中没有清单时返回 nil
+func readManifest(dir, templateSet string) (*TemplateManifest, error) {
+	path := filepath.Join(dir, manifestFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template set manifest: %w", err)
+	}
+
+	var manifest TemplateManifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid template set manifest %s: %w", path, err)
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid template set manifest %s: %w", path, err)
+	}
+	if manifest.MinGounoVersion != "" {
+		if c, _ := compareVersions(gouno.Version, manifest.MinGounoVersion); c < 0 {
+			return nil, fmt.Errorf("template set %q requires gouno >= %s, but this is gouno %s (upgrade gouno or use another template set)",
+				templateSet, manifest.MinGounoVersion, gouno.Version)
+		}
+	}
+	return &manifest, nil
+}
+
+// validate 检查清单字段的合法性
+func (m *TemplateManifest) validate() error {
+	for field, version := range map[string]string{"version": m.Version, "min-gouno-version": m.MinGounoVersion} {
+		if version == "" {
+			continue
+		}
+		if _, err := parseVersion(version); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+	}
+	if m.Extends != "" {
+		if err := checkTemplateSetName(m.Extends); err != nil {
+			return fmt.Errorf("extends: %w", err)
+		}
+	}
+	for typeName, path := range m.Paths {
+		if filepath.IsAbs(path) || strings.HasPrefix(filepath.Clean(path), "..") {
+			return fmt.Errorf("paths.%s: %q must be relative to the project root", typeName, path)
+		}
+	}
+	for name, kind := range m.Kinds {
+		if !kindNamePattern.MatchString(name) {
+			return fmt.Errorf("kinds.%s: invalid name (use lowercase letters, digits and underscores)", name)
+		}
+		if isBuiltinCommand(name) {
+			return fmt.Errorf("kinds.%s: conflicts with the builtin %s generator", name, name)
+		}
+		if filepath.IsAbs(kind.Path) || strings.HasPrefix(filepath.Clean(kind.Path), "..") {
+			return fmt.Errorf("kinds.%s.path: %q must be relative to the project root", name, kind.Path)
+		}
+	}
+	for i, hook := range m.Hooks {
+		if strings.TrimSpace(hook.Run) == "" {
+			return fmt.Errorf("hooks[%d]: run is required", i)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, v := range m.Variables {
+		if !token.IsIdentifier(v.Name) {
+			return fmt.Errorf("variables[%d]: invalid name %q (must be a Go identifier)", i, v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("variables[%d]: duplicate variable %q", i, v.Name)
+		}
+		seen[v.Name] = true
+		if v.Pattern != "" {
+			if _, err := regexp.Compile(v.Pattern); err != nil {
+				return fmt.Errorf("variable %s: invalid pattern: %w", v.Name, err)
+			}
+		}
+		if v.Default != "" {
+			if err := v.check(v.Default); err != nil {
+				return fmt.Errorf("variable %s: default %w", v.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// inherit 合并父模板集的清单：
+// 支持的类型取并集（任一方不限制时不限制），目录、自定义类型与变量以本模板集的声明优先，未声明钩子时沿用父模板集的钩子
+func (m *TemplateManifest) inherit(parent *TemplateManifest) {
+	m.chain = []string{m.Extends}
+	if parent == nil {
+		m.Types = nil
+		return
+	}
+	m.chain = parent.inheritance(m.Extends)
+
+	if len(m.Types) == 0 || len(parent.Types) == 0 {
+		m.Types = nil
+	} else {
+		for _, typeName := range parent.Types {
+			if !slices.Contains(m.Types, typeName) {
+				m.Types = append(m.Types, typeName)
+			}
+		}
+	}
+	for typeName, path := range parent.Paths {
+		if _, ok := m.Paths[typeName]; !ok {
+			if m.Paths == nil {
+				m.Paths = make(map[string]string)
+			}
+			m.Paths[typeName] = path
+		}
+	}
+	for name, kind := range parent.Kinds {
+		if _, ok := m.Kinds[name]; !ok {
+			if m.Kinds == nil {
+				m.Kinds = make(map[string]TemplateKind)
+			}
+			m.Kinds[name] = kind
+		}
+	}
+	if len(m.Hooks) == 0 {
+		m.Hooks = parent.Hooks
+	}
+	for _, v := range parent.Variables {
+		if !slices.ContainsFunc(m.Variables, func(own TemplateVariable) bool { return own.Name == v.Name }) {
+			m.Variables = append(m.Variables, v)
+		}
+	}
+}
+
+// inheritance 返回从 templateSet 开始、按查找顺序排列的继承链
+func (m *TemplateManifest) inheritance(templateSet string) []string {
+	if m == nil || m.Extends == "" {
+		return []string{templateSet}
+	}
+	return append([]string{templateSet}, m.chain...)
+}
+
+// supports 判断模板集是否支持指定模板，<type>_test 模板随 <type> 一起声明，自定义类型总是支持
+func (m *TemplateManifest) supports(templateName string) bool {
+	if m == nil || len(m.Types) == 0 {
+		return true
+	}
+	typeName := strings.TrimSuffix(templateName, "_test")
+	_, isKind := m.Kinds[typeName]
+	return isKind || slices.Contains(m.Types, templateName) || slices.Contains(m.Types, typeName)
+}
+
+// path 返回清单中为指定类型声明的默认目录（paths 优先于自定义类型的 path），未声明时返回空字符串
+func (m *TemplateManifest) path(typeName string) string {
+	if m == nil {
+		return ""
+	}
+	kind, _ := m.kind(typeName)
+	return cmp.Or(m.Paths[typeName], kind.Path)
+}
+
+// kind 返回清单中声明的自定义类型
+func (m *TemplateManifest) kind(name string) (TemplateKind, bool) {
+	if m == nil {
+		return TemplateKind{}, false
+	}
+	kind, ok := m.Kinds[name]
+	return kind, ok
+}
+
+// check 检查变量取值是否满足 required、choices 与 pattern
+func (v TemplateVariable) check(value string) error {
+	if value == "" {
+		if v.Required {
+			return errors.New("is required")
+		}
+		return nil
+	}
+	if len(v.Choices) > 0 && !slices.Contains(v.Choices, value) {
+		return fmt.Errorf("%q must be one of: %s", value, strings.Join(v.Choices, ", "))
+	}
+	if v.Pattern != "" && !regexp.MustCompile(v.Pattern).MatchString(value) {
+		return fmt.Errorf("%q must match %s", value, v.Pattern)
+	}
+	return nil
+}
+
+// outputPath 返回生成文件的目录（相对于项目根目录）：
+// 套件成员的目录 > --path > 模板集清单中的 paths > 默认目录
+func outputPath(cmd *cobra.Command, manifest *TemplateManifest, typeName, defaultPath string) string {
+	if path, ok := currentSession.paths[typeName]; ok {
+		return path
+	}
+	if flag := cmd.Flag("path"); flag != nil && flag.Changed {
+		return flag.Value.String()
+	}
+	return cmp.Or(manifest.path(typeName), defaultPath)
+}
+
+// templateVariables 返回模板集变量的取值，同一次命令执行中每个模板集只解析（和询问）一次
+func templateVariables(cmd *cobra.Command, templateSet string, manifest *TemplateManifest) (map[string]string, error) {
+	if vars, ok := currentSession.variables[templateSet]; ok {
+		return vars, nil
+	}
+
+	vars := make(map[string]string)
+	if cfg := loadProjectConfig(); cfg != nil {
+		for name, value := range cfg.Variables {
+			vars[name] = value
+		}
+	}
+	vars, err := resolveVariables(cmd, manifest, vars)
+	if err != nil {
+		return nil, err
+	}
+	currentSession.variables[templateSet] = vars
+	return vars, nil
+}
+
+// resolveVariables 在已知的取值上应用 --var，询问清单中仍未设置的变量并检查所有取值
+func resolveVariables(cmd *cobra.Command, manifest *TemplateManifest, vars map[string]string) (map[string]string, error) {
+	if flag := cmd.Flag("var"); flag != nil {
+		values, _ := cmd.Flags().GetStringArray("var")
+		for _, assignment := range values {
+			name, value, ok := strings.Cut(assignment, "=")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("invalid --var %q, expected name=value", assignment)
+			}
+			vars[name] = value
+		}
+	}
+
+	if manifest != nil {
+		for _, v := range manifest.Variables {
+			value, ok := vars[v.Name]
+			if !ok {
+				value = promptVariable(cmd, v)
+			}
+			if err := v.check(value); err != nil {
+				return nil, fmt.Errorf("template variable %s %w (set it with --var %s=<value>)", v.Name, err, v.Name)
+			}
+			vars[v.Name] = value
+		}
+	}
+	return vars, nil
+}
+
+// promptVariable 在交互式终端中询问变量取值，非交互或输入结束时使用默认值
+func promptVariable(cmd *cobra.Command, v TemplateVariable) string {
+	in := cmd.InOrStdin()
+	if f, ok := in.(*os.File); ok {
+		if !isatty.IsTerminal(f.Fd()) && !isatty.IsCygwinTerminal(f.Fd()) {
+			return v.Default
+		}
+	}
+	if currentSession.input == nil {
+		currentSession.input = bufio.NewReader(in)
+	}
+
+	prompt := v.Prompt
+	if prompt == "" {
+		prompt = v.Name
+	}
+	if len(v.Choices) > 0 {
+		prompt += " (" + strings.Join(v.Choices, "/") + ")"
+	}
+	if v.Default != "" {
+		prompt += " [" + v.Default + "]"
+	}
+	for {
+		cmd.Printf("%s: ", prompt)
+		line, err := currentSession.input.ReadString('\n')
+		value := strings.TrimSpace(line)
+		if value == "" {
+			value = v.Default
+		}
+		if err != nil {
+			// 输入结束时不再重复询问
+			cmd.Println()
+			return value
+		}
+		if checkErr := v.check(value); checkErr != nil {
+			cmd.Printf("%s %v\n", v.Name, checkErr)
+			continue
+		}
+		return value
+	}
+}
+
+// parseVersion 解析 1.2.3、v1.2、1.2.3-rc.1 形式的版本号，返回主、次、修订号与预发布标识
+func parseVersion(version string) ([4]string, error) {
+	var parts [4]string
+	v := strings.TrimPrefix(version, "v")
+	v, parts[3], _ = strings.Cut(v, "-")
+	numbers := strings.Split(v, ".")
+	if len(numbers) > 3 {
+		return parts, fmt.Errorf("invalid version %q", version)
+	}
+	for i := range 3 {
+		parts[i] = "0"
+		if i < len(numbers) {
+			if _, err := strconv.ParseUint(numbers[i], 10, 64); err != nil {
+				return parts, fmt.Errorf("invalid version %q", version)
+			}
+			parts[i] = numbers[i]
+		}
+	}
+	return parts, nil
+}
+
+// compareVersions 比较两个版本号，a < b 返回 -1，相等返回 0，a > b 返回 1
+// 带预发布标识的版本小于对应的正式版本
+func compareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range 3 {
+		x, _ := strconv.ParseUint(va[i], 10, 64)
+		y, _ := strconv.ParseUint(vb[i], 10, 64)
+		if x != y {
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	switch {
+	case va[3] == vb[3]:
+		return 0, nil
+	case va[3] == "":
+		return 1, nil
+	case vb[3] == "":
+		return -1, nil
+	}
+	return strings.Compare(va[3], vb[3]), nil
+}
@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.2", "1.2.0", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.1", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, err := compareVersions(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("compareVersions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("compareVersions(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"1.x", "1.2.3.4", ""} {
+		if _, err := parseVersion(invalid); err == nil {
+			t.Errorf("parseVersion(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeManifest := func(set, content string) {
+		dir := filepath.Join(home, templateDirName, templatesDirName, set)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, manifestFileName), []byte(content), 0644)
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		manifest, err := loadManifest("none")
+		if err != nil || manifest != nil {
+			t.Errorf("loadManifest() = %v, %v; want nil, nil", manifest, err)
+		}
+		if !manifest.supports("domain") || manifest.path("domain") != "" {
+			t.Error("a missing manifest should support every type without path overrides")
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		writeManifest("valid", "name: valid\nversion: 1.0.0\ntypes: [domain]\npaths:\n  domain: pkg/model\n")
+		manifest, err := loadManifest("valid")
+		if err != nil {
+			t.Fatalf("loadManifest() error = %v", err)
+		}
+		if !manifest.supports("domain") || !manifest.supports("domain_test") || manifest.supports("service") {
+			t.Errorf("unexpected supported types: %v", manifest.Types)
+		}
+		if got := manifest.path("domain"); got != "pkg/model" {
+			t.Errorf("path(domain) = %q; want pkg/model", got)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown field", "nmae: typo\n", "field nmae not found"},
+		{"bad version", "version: one\n", "invalid version"},
+		{"absolute path", "paths:\n  domain: /tmp/domain\n", "must be relative"},
+		{"bad variable", "variables:\n  - name: 1st\n", "invalid name"},
+		{"duplicate variable", "variables:\n  - name: a\n  - name: a\n", "duplicate variable"},
+		{"bad default", "variables:\n  - name: a\n    default: x\n    choices: [y, z]\n", "must be one of"},
+		{"too new", "min-gouno-version: 99.0.0\n", "requires gouno >= 99.0.0"},
+		{"bad kind", "kinds:\n  Event: {}\n", "kinds.Event: invalid name"},
+		{"builtin kind", "kinds:\n  service: {}\n", "conflicts with the builtin service generator"},
+		{"kind path", "kinds:\n  event:\n    path: ../event\n", "must be relative"},
+		{"empty hook", "hooks:\n  - types: [domain]\n", "hooks[0]: run is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := strings.ReplaceAll(tt.name, " ", "-")
+			writeManifest(set, tt.content)
+			if _, err := loadManifest(set); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadManifest() error = %v; want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateVariables(t *testing.T) {
+	cwd, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	t.Cleanup(func() { os.Chdir(cwd) })
+	os.WriteFile(configFileName, []byte("variables:\n  team: config-team\n  owner: config-owner\n"), 0644)
+
+	manifest := &TemplateManifest{Variables: []TemplateVariable{
+		{Name: "team"},
+		{Name: "owner"},
+		{Name: "license", Default: "MIT", Choices: []string{"MIT", "Apache-2.0"}},
+		{Name: "module", Required: true, Pattern: `^[a-z]+$`},
+	}}
+
+	tests := []struct {
+		name    string
+		vars    []string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "precedence",
+			vars: []string{"owner=flag-owner", "module=shop"},
+			want: map[string]string{"team": "config-team", "owner": "flag-owner", "license": "MIT", "module": "shop"},
+		},
+		{name: "required", wantErr: "template variable module is required"},
+		{name: "pattern", vars: []string{"module=Shop"}, wantErr: "must match"},
+		{name: "choices", vars: []string{"module=shop", "license=GPL"}, wantErr: "must be one of"},
+		{name: "malformed", vars: []string{"module"}, wantErr: "expected name=value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentSession = newSession()
+			cmd := &cobra.Command{}
+			cmd.Flags().StringArray("var", nil, "")
+			for _, v := range tt.vars {
+				cmd.Flags().Set("var", v)
+			}
+
+			got, err := templateVariables(cmd, "test", manifest)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("templateVariables() error = %v; want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("templateVariables() error = %v", err)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %q; want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestManifestInheritance(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeManifest := func(set, content string) {
+		dir := filepath.Join(home, templateDirName, templatesDirName, set)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, manifestFileName), []byte(content), 0644)
+	}
+	writeManifest("base", "extends: default\ntypes: [service]\npaths:\n  service: internal/app\n  domain: internal/model\nvariables:\n  - name: owner\n    default: base\n")
+	writeManifest("gorm", "extends: base\ntypes: [repository]\npaths:\n  domain: pkg/model\nvariables:\n  - name: owner\n    default: gorm\n  - name: db\n")
+	writeManifest("strict", "types: [domain]\n")
+	writeManifest("child", "extends: strict\ntypes: [service]\n")
+
+	t.Run("merged", func(t *testing.T) {
+		manifest, err := loadManifest("gorm")
+		if err != nil {
+			t.Fatalf("loadManifest() error = %v", err)
+		}
+		if got := strings.Join(manifest.inheritance("gorm"), ","); got != "gorm,base,default" {
+			t.Errorf("inheritance = %s; want gorm,base,default", got)
+		}
+		if manifest.Types != nil {
+			t.Errorf("types = %v; want unrestricted (default does not restrict types)", manifest.Types)
+		}
+		if manifest.path("domain") != "pkg/model" || manifest.path("service") != "internal/app" {
+			t.Errorf("paths = %v", manifest.Paths)
+		}
+		if len(manifest.Variables) != 2 || manifest.Variables[0].Default != "gorm" {
+			t.Errorf("variables = %+v", manifest.Variables)
+		}
+	})
+
+	t.Run("types union", func(t *testing.T) {
+		manifest, err := loadManifest("child")
+		if err != nil {
+			t.Fatalf("loadManifest() error = %v", err)
+		}
+		if !manifest.supports("service") || !manifest.supports("domain") || manifest.supports("task") {
+			t.Errorf("types = %v; want [service domain]", manifest.Types)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		writeManifest("a", "extends: b\n")
+		writeManifest("b", "extends: a\n")
+		if _, err := loadManifest("a"); err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+			t.Errorf("loadManifest() error = %v; want extends cycle", err)
+		}
+	})
+
+	t.Run("unknown parent", func(t *testing.T) {
+		writeManifest("orphan", "extends: missing\n")
+		if _, err := loadManifest("orphan"); err == nil || !strings.Contains(err.Error(), `extends unknown template set "missing"`) {
+			t.Errorf("loadManifest() error = %v; want unknown parent", err)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const cacheDirName = "cache"
+
+// 三方合并冲突标记
+const (
+	conflictStart = "<<<<<<< current"
+	conflictSep   = "======="
+	conflictEnd   = ">>>>>>> generated"
+)
+
+// pristinePath 返回生成文件的原始输出在 .gouno/cache 下的记录位置
+// 文件不在项目目录内时返回空字符串
+func pristinePath(filePath string) (string, error) {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	rel, err := filepath.Rel(projectRoot, filePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", nil
+	}
+	return filepath.Join(projectRoot, templateDirName, cacheDirName, rel), nil
+}
+
+// savePristine 记录生成文件的原始输出，作为之后三方合并的基准
+func savePristine(filePath, content string) error {
+	cachePath, err := pristinePath(filePath)
+	if err != nil || cachePath == "" {
+		return err
+	}
+	currentSession.tx.recordDir(filepath.Dir(cachePath))
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	currentSession.tx.recordFile(cachePath)
+	if err := os.WriteFile(cachePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to record generated output: %w", err)
+	}
+	return nil
+}
+
+// loadPristine 读取生成文件上一次的原始输出，不存在时返回 false
+func loadPristine(filePath string) (string, bool) {
+	cachePath, err := pristinePath(filePath)
+	if err != nil || cachePath == "" {
+		return "", false
+	}
+	content, err := os.ReadFile(cachePath)
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+// contentHash 返回内容的 SHA-256 摘要，用于判断生成文件是否被修改
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// mergeHunk 表示将 base[Start:End] 替换为 Lines
+type mergeHunk struct {
+	Start, End int
+	Lines      []string
+}
+
+// diffHunks 将 base 到 other 的行级差异转换为基于 base 行号的替换块
+func diffHunks(base, other []string) []mergeHunk {
+	var hunks []mergeHunk
+	pos := 0
+	var cur *mergeHunk
+	for _, op := range diffLines(base, other) {
+		if op.Kind == ' ' {
+			if cur != nil {
+				hunks = append(hunks, *cur)
+				cur = nil
+			}
+			pos++
+			continue
+		}
+		if cur == nil {
+			cur = &mergeHunk{Start: pos, End: pos}
+		}
+		if op.Kind == '-' {
+			cur.End++
+			pos++
+		} else {
+			cur.Lines = append(cur.Lines, op.Line)
+		}
+	}
+	if cur != nil {
+		hunks = append(hunks, *cur)
+	}
+	return hunks
+}
+
+// applyHunks 将 hunks 应用到 base[start:end]
+func applyHunks(base []string, start, end int, hunks []mergeHunk) []string {
+	var out []string
+	pos := start
+	for _, h := range hunks {
+		out = append(out, base[pos:h.Start]...)
+		out = append(out, h.Lines...)
+		pos = h.End
+	}
+	return append(out, base[pos:end]...)
+}
+
+// mergeLines 以 base 为共同祖先，对用户修改后的 current 与新生成的 generated 进行三方合并
+// 双方修改了同一区域且结果不同时写入冲突标记，返回合并结果与冲突数量
+func mergeLines(base, current, generated string) (string, int) {
+	baseLines := splitLines(base)
+	ours := diffHunks(baseLines, splitLines(current))
+	theirs := diffHunks(baseLines, splitLines(generated))
+
+	var out []string
+	conflicts := 0
+	pos := 0
+	for len(ours) > 0 || len(theirs) > 0 {
+		// 从最早开始的变更出发，合并所有与之重叠的变更为一个区域
+		start := len(baseLines)
+		if len(ours) > 0 {
+			start = ours[0].Start
+		}
+		if len(theirs) > 0 && theirs[0].Start < start {
+			start = theirs[0].Start
+		}
+		end := start
+		var regionOurs, regionTheirs []mergeHunk
+		for {
+			if len(ours) > 0 && overlaps(ours[0], start, end) {
+				end = max(end, ours[0].End)
+				regionOurs = append(regionOurs, ours[0])
+				ours = ours[1:]
+				continue
+			}
+			if len(theirs) > 0 && overlaps(theirs[0], start, end) {
+				end = max(end, theirs[0].End)
+				regionTheirs = append(regionTheirs, theirs[0])
+				theirs = theirs[1:]
+				continue
+			}
+			break
+		}
+
+		out = append(out, baseLines[pos:start]...)
+		oursLines := applyHunks(baseLines, start, end, regionOurs)
+		theirsLines := applyHunks(baseLines, start, end, regionTheirs)
+		switch {
+		case len(regionTheirs) == 0:
+			out = append(out, oursLines...)
+		case len(regionOurs) == 0 || slices.Equal(oursLines, theirsLines):
+			out = append(out, theirsLines...)
+		default:
+			conflicts++
+			out = append(out, conflictStart+"\n")
+			out = append(out, withTrailingNewline(oursLines)...)
+			out = append(out, conflictSep+"\n")
+			out = append(out, withTrailingNewline(theirsLines)...)
+			out = append(out, conflictEnd+"\n")
+		}
+		pos = end
+	}
+	out = append(out, baseLines[pos:]...)
+	return strings.Join(out, ""), conflicts
+}
+
+// overlaps 判断变更块是否与区域 [start, end) 重叠，同一位置的插入也视为重叠
+func overlaps(h mergeHunk, start, end int) bool {
+	return h.Start < end || h.Start == start
+}
+
+func withTrailingNewline(lines []string) []string {
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines = append(slices.Clone(lines[:n-1]), lines[n-1]+"\n")
+	}
+	return lines
+}
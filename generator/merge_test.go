@@ -0,0 +1,59 @@
+package generator
+
+import "testing"
+
+func TestMergeLines(t *testing.T) {
+	base := "package a\n\ntype A struct {\n}\n\nfunc Foo() {\n}\n"
+
+	tests := []struct {
+		name      string
+		current   string
+		generated string
+		want      string
+		conflicts int
+	}{
+		{
+			name:      "only generated changed",
+			current:   base,
+			generated: "package a\n\ntype A struct {\n\tID int\n}\n\nfunc Foo() {\n}\n",
+			want:      "package a\n\ntype A struct {\n\tID int\n}\n\nfunc Foo() {\n}\n",
+		},
+		{
+			name:      "only current changed",
+			current:   "package a\n\ntype A struct {\n}\n\nfunc Foo() {\n\tprintln()\n}\n",
+			generated: base,
+			want:      "package a\n\ntype A struct {\n}\n\nfunc Foo() {\n\tprintln()\n}\n",
+		},
+		{
+			name:      "both changed different regions",
+			current:   "package a\n\ntype A struct {\n}\n\nfunc Foo() {\n\tprintln()\n}\n",
+			generated: "package a\n\ntype A struct {\n\tID int\n}\n\nfunc Foo() {\n}\n",
+			want:      "package a\n\ntype A struct {\n\tID int\n}\n\nfunc Foo() {\n\tprintln()\n}\n",
+		},
+		{
+			name:      "both changed same way",
+			current:   "package b\n\ntype A struct {\n}\n\nfunc Foo() {\n}\n",
+			generated: "package b\n\ntype A struct {\n}\n\nfunc Foo() {\n}\n",
+			want:      "package b\n\ntype A struct {\n}\n\nfunc Foo() {\n}\n",
+		},
+		{
+			name:      "conflict",
+			current:   "package a\n\ntype A struct {\n\tName string\n}\n\nfunc Foo() {\n}\n",
+			generated: "package a\n\ntype A struct {\n\tID int\n}\n\nfunc Foo() {\n}\n",
+			want:      "package a\n\ntype A struct {\n<<<<<<< current\n\tName string\n=======\n\tID int\n>>>>>>> generated\n}\n\nfunc Foo() {\n}\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := mergeLines(base, tt.current, tt.generated)
+			if got != tt.want {
+				t.Errorf("mergeLines =\n%s\nwant:\n%s", got, tt.want)
+			}
+			if conflicts != tt.conflicts {
+				t.Errorf("conflicts = %d; want %d", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
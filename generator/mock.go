@@ -0,0 +1,359 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rushairer/gouno/utility"
+	"github.com/spf13/cobra"
+)
+
+var mockCmd = &cobra.Command{
+	Use:                   "mock [name]",
+	Short:                 "Generate fake implementation of a repository or service",
+	Aliases:               []string{"m"},
+	Args:                  cobra.ExactArgs(1),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, _ := cmd.Flags().GetString("kind")
+		source, ok := mockSources[kind]
+		if !ok {
+			return fmt.Errorf("unsupported mock kind %q (supported: repository, service)", kind)
+		}
+		manifest, err := loadManifest(resolveTemplateSet(cmd))
+		if err != nil {
+			return err
+		}
+		// 嵌套名称（如 admin/user）的被模拟类型位于源码目录的分组子目录中
+		group, _ := splitName(args[0])
+		source = filepath.Join(cmp.Or(manifest.path(kind), source), filepath.FromSlash(group))
+		if flag := cmd.Flag("source"); flag != nil && flag.Changed {
+			source = flag.Value.String()
+		}
+		path := filepath.Join(source, mockDirName)
+		if flag := cmd.Flag("path"); flag != nil && flag.Changed {
+			path = flag.Value.String()
+		}
+		_, err = generateMock(cmd, args[0], kind, source, path, nil)
+		return err
+	},
+}
+
+// mockSources 是各类型被模拟时默认的源码目录，模板集清单中的 paths 优先
+var mockSources = map[string]string{
+	"repository": defaultRepositoryPath,
+	"service":    defaultServicePath,
+}
+
+const mockDirName = "mock"
+
+func init() {
+	mockCmd.Flags().StringP("path", "p", "", "path to mock (default <source>/mock)")
+	mockCmd.Flags().String("kind", "repository", "type to mock: repository or service")
+	mockCmd.Flags().String("source", "", "path to the package declaring the mocked type (default by kind)")
+	mockCmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(mockCmd)
+}
+
+// mockSpec 是 mock 模板使用的被模拟类型信息，模板中通过 .Mock 访问
+type mockSpec struct {
+	TypeName    string       // 被模拟的类型名，如 UserRepository
+	Package     string       // 被模拟类型所在包的包名
+	ImportPath  string       // 被模拟类型所在包的导入路径
+	IsInterface bool         // 被模拟类型是否为接口，是则生成编译期实现检查
+	Imports     []mockImport // 方法签名需要的导入
+	Methods     []mockMethod
+}
+
+type mockImport struct {
+	Name string // 包名冲突时使用的别名，否则为空
+	Path string
+}
+
+type mockMethod struct {
+	Name    string
+	Params  []mockVar
+	Results []mockVar
+}
+
+type mockVar struct {
+	Name      string // 参数变量名
+	Field     string // 记录在 Calls / Returns 结构中的字段名
+	Type      string // 签名中的类型，可变参数为 ...T
+	FieldType string // 字段类型，可变参数为 []T
+	Arg       string // 转发给 Stub 时的实参，可变参数为 name...
+}
+
+// generateMock 通过 go/types 解析 sourcePath 中 <Name><Kind> 类型的方法集，在 path 下生成带调用记录与可配置返回值的 fake
+// overlay 用于替换磁盘上的源码（--dry-run 时尚未写入的文件）
+func generateMock(cmd *cobra.Command, name, kind, sourcePath, path string, overlay map[string]string) (*generatedFile, error) {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	module := readModulePath(projectRoot)
+	if module == "" {
+		return nil, fmt.Errorf("mock requires a go.mod in %s to resolve import paths", projectRoot)
+	}
+
+	sourceDir := filepath.Join(projectRoot, sourcePath)
+	typeName := structName(name) + utility.ToCamelCase(kind)
+	spec, err := inspectMockType(sourceDir, dirImportPath(module, projectRoot, sourceDir), typeName, overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	templateSet := resolveTemplateSet(cmd)
+	manifest, err := loadManifest(templateSet)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(projectRoot, path)
+	group, base := splitName(name)
+	data := &templateData{
+		Name:        name,
+		Group:       group,
+		StructName:  structName(name),
+		Snake:       utility.ToSnakeCase(structName(name)),
+		Package:     packageName(dir, mockDirName),
+		Module:      module,
+		TemplateSet: templateSet,
+		Packages:    layerImports(module, manifest, group),
+		Mock:        spec,
+	}
+	return writeTemplateFile(cmd, "mock", "mock", filepath.Join(dir, base+".go"), data)
+}
+
+// inspectMockType 对 dir 中的包进行类型检查，返回指定类型的导出方法集
+func inspectMockType(dir, importPath, typeName string, overlay map[string]string) (*mockSpec, error) {
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	for path := range overlay {
+		if filepath.Dir(path) == dir && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		var src any
+		if content, ok := overlay[path]; ok {
+			src = content
+		}
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found in %s", dir)
+	}
+
+	var typeErrors []error
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(err error) { typeErrors = append(typeErrors, err) },
+	}
+	pkg, _ := conf.Check(importPath, fset, files, nil)
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, dir)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a named type", typeName)
+	}
+
+	spec := &mockSpec{
+		TypeName:   typeName,
+		Package:    pkg.Name(),
+		ImportPath: importPath,
+	}
+	imports := newMockImports()
+	qualifier := imports.qualifier
+
+	var methods []*types.Func
+	if iface, ok := named.Underlying().(*types.Interface); ok {
+		spec.IsInterface = true
+		imports.add(pkg)
+		for i := 0; i < iface.NumMethods(); i++ {
+			methods = append(methods, iface.Method(i))
+		}
+	} else {
+		mset := types.NewMethodSet(types.NewPointer(named))
+		for i := 0; i < mset.Len(); i++ {
+			methods = append(methods, mset.At(i).Obj().(*types.Func))
+		}
+	}
+
+	for _, fn := range methods {
+		if !fn.Exported() {
+			continue
+		}
+		sig := fn.Type().(*types.Signature)
+		if hasInvalidType(sig) {
+			if len(typeErrors) > 0 {
+				return nil, fmt.Errorf("failed to resolve types of %s.%s: %w", typeName, fn.Name(), typeErrors[0])
+			}
+			return nil, fmt.Errorf("failed to resolve types of %s.%s", typeName, fn.Name())
+		}
+		spec.Methods = append(spec.Methods, mockMethod{
+			Name:    fn.Name(),
+			Params:  mockVars(sig.Params(), sig.Variadic(), qualifier, false),
+			Results: mockVars(sig.Results(), false, qualifier, true),
+		})
+	}
+	spec.Imports = imports.list
+	return spec, nil
+}
+
+// mockVars 为参数或返回值生成变量名与字段名，未命名时按类型或位置命名
+func mockVars(tuple *types.Tuple, variadic bool, qualifier types.Qualifier, results bool) []mockVar {
+	vars := make([]mockVar, tuple.Len())
+	used := map[string]bool{"fake": true, "stub": true, "returns": true}
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		typ := types.TypeString(v.Type(), qualifier)
+		fieldType := typ
+		isVariadic := variadic && i == tuple.Len()-1
+		if isVariadic {
+			typ = "..." + types.TypeString(v.Type().(*types.Slice).Elem(), qualifier)
+		}
+
+		name := v.Name()
+		if name == "" || name == "_" || used[name] {
+			name = fmt.Sprintf("p%d", i)
+			if results {
+				name = fmt.Sprintf("r%d", i)
+				if typ == "error" && !used["err"] {
+					name = "err"
+				}
+			}
+		}
+		used[name] = true
+		arg := name
+		if isVariadic {
+			arg += "..."
+		}
+		vars[i] = mockVar{Name: name, Field: fieldName(name), Type: typ, FieldType: fieldType, Arg: arg}
+	}
+	return vars
+}
+
+func hasInvalidType(sig *types.Signature) bool {
+	for _, tuple := range []*types.Tuple{sig.Params(), sig.Results()} {
+		for i := 0; i < tuple.Len(); i++ {
+			if strings.Contains(types.TypeString(tuple.At(i).Type(), nil), "invalid type") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mockImports 记录 fake 中引用到的包，并为同名包分配别名
+type mockImports struct {
+	list  []mockImport
+	names map[string]string // 导入路径 -> 引用名
+}
+
+func newMockImports() *mockImports {
+	return &mockImports{names: make(map[string]string)}
+}
+
+func (m *mockImports) add(pkg *types.Package) string {
+	if name, ok := m.names[pkg.Path()]; ok {
+		return name
+	}
+	name := pkg.Name()
+	alias := ""
+	for i := 2; slices.ContainsFunc(m.list, func(imp mockImport) bool { return m.names[imp.Path] == name }); i++ {
+		name = pkg.Name() + strconv.Itoa(i)
+		alias = name
+	}
+	m.names[pkg.Path()] = name
+	m.list = append(m.list, mockImport{Name: alias, Path: pkg.Path()})
+	return name
+}
+
+func (m *mockImports) qualifier(pkg *types.Package) string {
+	return m.add(pkg)
+}
+
+const mockTemplate = `// Code generated by gouno gen mock; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"sync"
+{{- range .Mock.Imports}}
+	{{if .Name}}{{.Name}} {{end}}"{{.Path}}"
+{{- end}}
+)
+{{- $fake := printf "Fake%s" .Mock.TypeName}}
+{{if .Mock.IsInterface}}
+var _ {{.Mock.Package}}.{{.Mock.TypeName}} = (*{{$fake}})(nil)
+{{end}}
+// {{$fake}} is a fake implementation of {{.Mock.Package}}.{{.Mock.TypeName}}.
+// Each method records its calls in <Method>Calls and returns <Method>Returns,
+// unless <Method>Stub is set.
+type {{$fake}} struct {
+	mu sync.Mutex
+{{range .Mock.Methods}}
+	{{.Name}}Stub    func({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Type}}{{end}}) ({{range $i, $r := .Results}}{{if $i}}, {{end}}{{$r.Type}}{{end}})
+	{{.Name}}Calls   []{{$fake}}{{.Name}}Call
+	{{.Name}}Returns {{$fake}}{{.Name}}Returns
+{{- end}}
+}
+{{range .Mock.Methods}}
+// {{$fake}}{{.Name}}Call records the arguments of a {{.Name}} call.
+type {{$fake}}{{.Name}}Call struct {
+{{- range .Params}}
+	{{.Field}} {{.FieldType}}
+{{- end}}
+}
+
+// {{$fake}}{{.Name}}Returns configures the values returned by {{.Name}}.
+type {{$fake}}{{.Name}}Returns struct {
+{{- range .Results}}
+	{{.Field}} {{.FieldType}}
+{{- end}}
+}
+
+func (fake *{{$fake}}) {{.Name}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) ({{range $i, $r := .Results}}{{if $i}}, {{end}}{{$r.Type}}{{end}}) {
+	fake.mu.Lock()
+	fake.{{.Name}}Calls = append(fake.{{.Name}}Calls, {{$fake}}{{.Name}}Call{ {{- range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Field}}: {{$p.Name}}{{end}}})
+	{{- if .Results}}
+	stub, returns := fake.{{.Name}}Stub, fake.{{.Name}}Returns
+	fake.mu.Unlock()
+
+	if stub != nil {
+		return stub({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Arg}}{{end}})
+	}
+	return {{range $i, $r := .Results}}{{if $i}}, {{end}}returns.{{$r.Field}}{{end}}
+	{{- else}}
+	stub := fake.{{.Name}}Stub
+	fake.mu.Unlock()
+
+	if stub != nil {
+		stub({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Arg}}{{end}})
+	}
+	{{- end}}
+}
+{{end}}`
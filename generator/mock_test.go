@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInspectMockType(t *testing.T) {
+	dir := t.TempDir()
+	src := `package store
+
+import "context"
+
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(context.Context, string, ...[]byte)
+	close()
+}
+`
+	os.WriteFile(filepath.Join(dir, "store.go"), []byte(src), 0644)
+
+	spec, err := inspectMockType(dir, "example.com/app/store", "Store", nil)
+	if err != nil {
+		t.Fatalf("inspectMockType failed: %v", err)
+	}
+	if !spec.IsInterface || spec.Package != "store" {
+		t.Errorf("spec = %+v; want interface in package store", spec)
+	}
+	if len(spec.Methods) != 2 {
+		t.Fatalf("methods = %+v; want exported Get and Put", spec.Methods)
+	}
+
+	get := spec.Methods[0]
+	if get.Params[1].Name != "key" || get.Results[0].Field != "R0" || get.Results[1].Field != "Err" {
+		t.Errorf("Get vars = %+v %+v", get.Params, get.Results)
+	}
+	put := spec.Methods[1]
+	want := mockVar{Name: "p2", Field: "P2", Type: "...[]byte", FieldType: "[][]byte", Arg: "p2..."}
+	if put.Params[2] != want {
+		t.Errorf("variadic param = %+v; want %+v", put.Params[2], want)
+	}
+
+	if _, err := inspectMockType(dir, "example.com/app/store", "Missing", nil); err == nil {
+		t.Error("expected error for missing type")
+	}
+
+	overlay := map[string]string{filepath.Join(dir, "extra.go"): "package store\n\ntype Extra struct{}\n\nfunc (Extra) Ping() {}\n"}
+	spec, err = inspectMockType(dir, "example.com/app/store", "Extra", overlay)
+	if err != nil || spec.IsInterface || len(spec.Methods) != 1 {
+		t.Errorf("overlay spec = %+v, %v; want struct with Ping", spec, err)
+	}
+}
+
+func TestIsGeneratedCode(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"// Code generated by gouno gen mock; DO NOT EDIT.\n\npackage mock\n", true},
+		{"package mock\n\n// Code generated by hand, edit freely.\n", false},
+		{"package mock\n", false},
+	}
+	for _, tt := range tests {
+		if got := isGeneratedCode([]byte(tt.src)); got != tt.want {
+			t.Errorf("isGeneratedCode(%q) = %v; want %v", tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,793 @@
+package generator
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rushairer/gouno/utility"
+	"gopkg.in/yaml.v3"
+)
+
+// orderedMap 按文档中出现的顺序解码 YAML 映射，使生成结果的顺序与文档一致
+type orderedMap[T any] struct {
+	Keys   []string
+	Values map[string]T
+}
+
+func (m *orderedMap[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+	m.Values = make(map[string]T, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		var value T
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		if _, ok := m.Values[key]; !ok {
+			m.Keys = append(m.Keys, key)
+		}
+		m.Values[key] = value
+	}
+	return nil
+}
+
+// openapiDoc 是生成代码需要的 OpenAPI 3 文档子集
+type openapiDoc struct {
+	OpenAPI string `yaml:"openapi"`
+	Swagger string `yaml:"swagger"`
+	Info    struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Tags []struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+	} `yaml:"tags"`
+	Paths      orderedMap[*openapiPathItem] `yaml:"paths"`
+	Components struct {
+		Schemas       orderedMap[*openapiSchema]     `yaml:"schemas"`
+		Parameters    map[string]*openapiParameter   `yaml:"parameters"`
+		RequestBodies map[string]*openapiRequestBody `yaml:"requestBodies"`
+		Responses     map[string]*openapiResponse    `yaml:"responses"`
+	} `yaml:"components"`
+}
+
+type openapiPathItem struct {
+	Parameters []*openapiParameter `yaml:"parameters"`
+	Get        *openapiOperation   `yaml:"get"`
+	Put        *openapiOperation   `yaml:"put"`
+	Post       *openapiOperation   `yaml:"post"`
+	Delete     *openapiOperation   `yaml:"delete"`
+	Options    *openapiOperation   `yaml:"options"`
+	Head       *openapiOperation   `yaml:"head"`
+	Patch      *openapiOperation   `yaml:"patch"`
+}
+
+// operations 返回路径下定义的操作，键为 HTTP 方法
+func (p *openapiPathItem) operations() ([]string, []*openapiOperation) {
+	var methods []string
+	var ops []*openapiOperation
+	for _, m := range []struct {
+		method string
+		op     *openapiOperation
+	}{
+		{"GET", p.Get}, {"POST", p.Post}, {"PUT", p.Put}, {"PATCH", p.Patch},
+		{"DELETE", p.Delete}, {"HEAD", p.Head}, {"OPTIONS", p.Options},
+	} {
+		if m.op != nil {
+			methods = append(methods, m.method)
+			ops = append(ops, m.op)
+		}
+	}
+	return methods, ops
+}
+
+type openapiOperation struct {
+	OperationID string                       `yaml:"operationId"`
+	Summary     string                       `yaml:"summary"`
+	Description string                       `yaml:"description"`
+	Tags        []string                     `yaml:"tags"`
+	Parameters  []*openapiParameter          `yaml:"parameters"`
+	RequestBody *openapiRequestBody          `yaml:"requestBody"`
+	Responses   orderedMap[*openapiResponse] `yaml:"responses"`
+}
+
+type openapiParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Schema      *openapiSchema `yaml:"schema"`
+}
+
+type openapiRequestBody struct {
+	Ref      string                        `yaml:"$ref"`
+	Required bool                          `yaml:"required"`
+	Content  orderedMap[*openapiMediaType] `yaml:"content"`
+}
+
+type openapiResponse struct {
+	Ref         string                        `yaml:"$ref"`
+	Description string                        `yaml:"description"`
+	Content     orderedMap[*openapiMediaType] `yaml:"content"`
+}
+
+type openapiMediaType struct {
+	Schema *openapiSchema `yaml:"schema"`
+}
+
+type openapiSchema struct {
+	Ref                  string                     `yaml:"$ref"`
+	Type                 schemaType                 `yaml:"type"`
+	Format               string                     `yaml:"format"`
+	Description          string                     `yaml:"description"`
+	Nullable             bool                       `yaml:"nullable"`
+	Required             []string                   `yaml:"required"`
+	Properties           orderedMap[*openapiSchema] `yaml:"properties"`
+	AdditionalProperties *additionalProperties      `yaml:"additionalProperties"`
+	Items                *openapiSchema             `yaml:"items"`
+	AllOf                []*openapiSchema           `yaml:"allOf"`
+	OneOf                []*openapiSchema           `yaml:"oneOf"`
+	AnyOf                []*openapiSchema           `yaml:"anyOf"`
+}
+
+// schemaType 兼容 OpenAPI 3.0 的 type: string 与 3.1 的 type: [string, "null"]
+type schemaType []string
+
+func (t *schemaType) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = schemaType{node.Value}
+		return nil
+	}
+	var types []string
+	if err := node.Decode(&types); err != nil {
+		return err
+	}
+	*t = types
+	return nil
+}
+
+// is 判断是否为指定类型（忽略 "null"）
+func (t schemaType) is(name string) bool {
+	return slices.Contains(t, name)
+}
+
+// additionalProperties 兼容布尔值与 schema 两种写法
+type additionalProperties struct {
+	Schema *openapiSchema
+}
+
+func (a *additionalProperties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var allowed bool
+		if err := node.Decode(&allowed); err != nil {
+			return err
+		}
+		if allowed {
+			a.Schema = &openapiSchema{}
+		}
+		return nil
+	}
+	a.Schema = &openapiSchema{}
+	return node.Decode(a.Schema)
+}
+
+// parseOpenAPI 解析 OpenAPI 3 文档（YAML 或 JSON）
+func parseOpenAPI(src []byte) (*openapiDoc, error) {
+	var doc openapiDoc
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		if doc.Swagger != "" {
+			return nil, fmt.Errorf("swagger %s documents are not supported, convert to OpenAPI 3 first", doc.Swagger)
+		}
+		return nil, fmt.Errorf("missing or unsupported openapi version %q (expected 3.x)", doc.OpenAPI)
+	}
+	return &doc, nil
+}
+
+// apiSpec 是 openapi 模板使用的数据，模板中通过 .API 访问
+type apiSpec struct {
+	Title      string
+	Version    string
+	Types      []*apiType      // DTO 文件中的类型
+	Imports    []string        // DTO 文件需要的导入
+	Tag        string          // 控制器对应的标签
+	Doc        string          // 标签说明
+	Operations []*apiOperation // 控制器中的处理函数
+}
+
+// apiType 是由 schema 生成的类型：Fields 非空时为结构体，否则为 Underlying 类型
+type apiType struct {
+	Name       string
+	Doc        string
+	Underlying string
+	Fields     []apiField
+}
+
+// apiField 是结构体字段，Name 为空时为嵌入字段
+type apiField struct {
+	Name string
+	Type string
+	Tag  string
+	Doc  string
+}
+
+// apiOperation 是一个 OpenAPI 操作对应的 gin 处理函数
+type apiOperation struct {
+	Handler string
+	Method  string // HTTP 方法，如 GET
+	Path    string // 文档中的路径，如 /users/{id}
+	Route   string // gin 路由路径，如 /users/:id
+	Summary string
+	Params  []apiBinding // 按顺序执行的参数绑定：路径、查询、请求头、请求体
+	Status  string       // 成功响应状态码表达式，如 http.StatusCreated
+	OK      bool         // 成功状态码是否为 200
+	Result  string       // 响应数据类型，无响应体时为空
+}
+
+// apiBinding 是处理函数中的一次参数绑定
+type apiBinding struct {
+	Var    string // 变量名
+	Type   string // 变量类型
+	Method string // gin.Context 上的绑定方法，如 ShouldBindUri
+}
+
+// openapiBuilder 将文档转为模板数据，并收集需要生成的类型
+type openapiBuilder struct {
+	doc   *openapiDoc
+	types []*apiType
+	names map[string]bool // 已使用的类型名
+}
+
+// buildOpenAPI 返回 DTO 类型与按标签分组的操作，标签按文档 tags 中的顺序排列，其余按首次出现顺序
+func buildOpenAPI(doc *openapiDoc) (*openapiBuilder, []string, map[string][]*apiOperation, error) {
+	b := &openapiBuilder{doc: doc, names: make(map[string]bool)}
+
+	// 组件 schema 名称先行占用，使操作生成的类型名不与其冲突
+	for _, name := range doc.Components.Schemas.Keys {
+		typeName := goTypeName(name)
+		if b.names[typeName] {
+			return nil, nil, nil, fmt.Errorf("schema %q conflicts with another schema named %s", name, typeName)
+		}
+		b.names[typeName] = true
+	}
+	for _, name := range doc.Components.Schemas.Keys {
+		if err := b.defineType(goTypeName(name), doc.Components.Schemas.Values[name]); err != nil {
+			return nil, nil, nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+
+	var tags []string
+	for _, tag := range doc.Tags {
+		tags = append(tags, tag.Name)
+	}
+	operations := make(map[string][]*apiOperation)
+	handlers := make(map[string]map[string]bool)
+	for _, path := range doc.Paths.Keys {
+		item := doc.Paths.Values[path]
+		if item == nil {
+			continue
+		}
+		methods, ops := item.operations()
+		for i, op := range ops {
+			tag := "default"
+			if len(op.Tags) > 0 {
+				tag = op.Tags[0]
+			} else if segment := firstPathSegment(path); segment != "" {
+				tag = segment
+			}
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+
+			apiOp, err := b.operation(methods[i], path, item.Parameters, op)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("%s %s: %w", methods[i], path, err)
+			}
+			if handlers[tag] == nil {
+				handlers[tag] = make(map[string]bool)
+			}
+			if handlers[tag][apiOp.Handler] {
+				return nil, nil, nil, fmt.Errorf("%s %s: duplicate handler %s in tag %s", methods[i], path, apiOp.Handler, tag)
+			}
+			handlers[tag][apiOp.Handler] = true
+			operations[tag] = append(operations[tag], apiOp)
+		}
+	}
+	tags = slices.DeleteFunc(tags, func(tag string) bool { return len(operations[tag]) == 0 })
+	return b, tags, operations, nil
+}
+
+// imports 返回 DTO 类型需要的导入
+func (b *openapiBuilder) imports() []string {
+	var imports []string
+	for _, t := range b.types {
+		types := []string{t.Underlying}
+		for _, f := range t.Fields {
+			types = append(types, f.Type)
+		}
+		for _, typ := range types {
+			for qualifier, pkg := range typeImports {
+				if strings.Contains(typ, qualifier+".") && !slices.Contains(imports, pkg) {
+					imports = append(imports, pkg)
+				}
+			}
+		}
+	}
+	slices.Sort(imports)
+	return imports
+}
+
+// uniqueName 返回未被占用的类型名
+func (b *openapiBuilder) uniqueName(name string) string {
+	candidate := name
+	for i := 2; b.names[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	b.names[candidate] = true
+	return candidate
+}
+
+// defineType 为 schema 生成名为 name 的类型定义
+func (b *openapiBuilder) defineType(name string, s *openapiSchema) error {
+	t := &apiType{Name: name, Doc: s.Description}
+	b.types = append(b.types, t)
+	if s.Ref == "" && isObjectSchema(s) && s.AdditionalProperties == nil {
+		fields, err := b.fields(name, s)
+		if err != nil {
+			return err
+		}
+		t.Fields = fields
+		if len(fields) > 0 {
+			return nil
+		}
+		t.Underlying = "struct{}"
+		return nil
+	}
+	typ, err := b.goType(name, s)
+	if err != nil {
+		return err
+	}
+	t.Underlying = typ
+	return nil
+}
+
+// fields 返回对象 schema 的结构体字段，allOf 中的 schema 会被合并
+func (b *openapiBuilder) fields(owner string, s *openapiSchema) ([]apiField, error) {
+	var fields []apiField
+	for _, part := range s.AllOf {
+		// 引用的组件 schema 以嵌入字段表示，JSON 编解码时字段会被展开
+		if part.Ref != "" {
+			typ, err := b.goType(owner, part)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, apiField{Type: typ})
+			continue
+		}
+		partFields, err := b.fields(owner, part)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range partFields {
+			if f.Name == "" || !slices.ContainsFunc(fields, func(existing apiField) bool { return existing.Name == f.Name }) {
+				fields = append(fields, f)
+			}
+		}
+	}
+	for _, prop := range s.Properties.Keys {
+		ps := s.Properties.Values[prop]
+		name := goTypeName(prop)
+		typ, err := b.goType(owner+name, ps)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", prop, err)
+		}
+		required := slices.Contains(s.Required, prop)
+		if isNullable(ps) && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && typ != "any" {
+			typ = "*" + typ
+		}
+		tag := fmt.Sprintf(`json:"%s"`, prop)
+		if required {
+			tag += ` binding:"required"`
+		} else {
+			tag = fmt.Sprintf(`json:"%s,omitempty"`, prop)
+		}
+		field := apiField{Name: name, Type: typ, Tag: tag, Doc: ps.Description}
+		if i := slices.IndexFunc(fields, func(existing apiField) bool { return existing.Name == name && name != "" }); i >= 0 {
+			fields[i] = field
+		} else {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// goType 返回 schema 对应的 Go 类型，内联对象会以 hint 为名生成新的类型
+func (b *openapiBuilder) goType(hint string, s *openapiSchema) (string, error) {
+	if s == nil {
+		return "any", nil
+	}
+	if s.Ref != "" {
+		name, err := schemaRefName(s.Ref)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := b.doc.Components.Schemas.Values[name]; !ok {
+			return "", fmt.Errorf("schema %s not found", s.Ref)
+		}
+		return goTypeName(name), nil
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return "json.RawMessage", nil
+	}
+
+	switch {
+	case s.Type.is("array"):
+		elem, err := b.goType(hint+"Item", s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case s.Type.is("string"):
+		switch s.Format {
+		case "date-time", "date":
+			return "time.Time", nil
+		case "byte", "binary":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case s.Type.is("integer"):
+		switch s.Format {
+		case "int32":
+			return "int32", nil
+		case "int64":
+			return "int64", nil
+		}
+		return "int", nil
+	case s.Type.is("number"):
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case s.Type.is("boolean"):
+		return "bool", nil
+	case isObjectSchema(s):
+		if len(s.Properties.Keys) == 0 && len(s.AllOf) == 0 {
+			if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+				elem, err := b.goType(hint+"Value", s.AdditionalProperties.Schema)
+				if err != nil {
+					return "", err
+				}
+				return "map[string]" + elem, nil
+			}
+			return "map[string]any", nil
+		}
+		if len(s.AllOf) == 1 && len(s.Properties.Keys) == 0 {
+			return b.goType(hint, s.AllOf[0])
+		}
+		name := b.uniqueName(hint)
+		if err := b.defineType(name, s); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	return "any", nil
+}
+
+func isObjectSchema(s *openapiSchema) bool {
+	return s.Type.is("object") || (len(s.Type) == 0 && (len(s.Properties.Keys) > 0 || len(s.AllOf) > 0 || s.AdditionalProperties != nil))
+}
+
+func isNullable(s *openapiSchema) bool {
+	return s != nil && (s.Nullable || s.Type.is("null"))
+}
+
+// operation 生成操作对应的处理函数描述与参数、请求体、响应类型
+func (b *openapiBuilder) operation(method, path string, shared []*openapiParameter, op *openapiOperation) (*apiOperation, error) {
+	handler := op.OperationID
+	if handler == "" {
+		handler = defaultHandlerName(method, path)
+	}
+	handler = goTypeName(handler)
+	apiOp := &apiOperation{
+		Handler: handler,
+		Method:  method,
+		Path:    path,
+		Route:   ginRoute(path),
+		Summary: strings.TrimSpace(op.Summary),
+	}
+
+	params, err := b.parameters(append(slices.Clone(shared), op.Parameters...))
+	if err != nil {
+		return nil, err
+	}
+	for _, in := range []struct{ in, suffix, tag, method string }{
+		{"path", "Path", "uri", "ShouldBindUri"},
+		{"query", "Query", "form", "ShouldBindQuery"},
+		{"header", "Header", "header", "ShouldBindHeader"},
+	} {
+		var fields []apiField
+		for _, p := range params {
+			if p.In != in.in {
+				continue
+			}
+			typ, err := b.goType(handler+in.suffix+goTypeName(p.Name), p.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+			}
+			tag := fmt.Sprintf(`%s:"%s"`, in.tag, p.Name)
+			if p.Required || in.in == "path" {
+				tag += ` binding:"required"`
+			}
+			fields = append(fields, apiField{Name: goTypeName(p.Name), Type: typ, Tag: tag, Doc: p.Description})
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		name := b.uniqueName(handler + in.suffix)
+		b.types = append(b.types, &apiType{Name: name, Doc: fmt.Sprintf("%s holds the %s parameters of %s %s.", name, in.in, method, path), Fields: fields})
+		apiOp.Params = append(apiOp.Params, apiBinding{Var: lowerFirst(in.suffix), Type: name, Method: in.method})
+	}
+
+	if op.RequestBody != nil {
+		body, err := b.requestBody(op.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		if mediaType, content := firstContent(body.Content); content != nil {
+			typ, err := b.goType(handler+"Request", content.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("request body: %w", err)
+			}
+			bind := "ShouldBindJSON"
+			if !strings.Contains(mediaType, "json") {
+				bind = "ShouldBind"
+			}
+			apiOp.Params = append(apiOp.Params, apiBinding{Var: "req", Type: typ, Method: bind})
+		}
+	}
+
+	code, resp, err := b.successResponse(op)
+	if err != nil {
+		return nil, err
+	}
+	apiOp.Status = statusExpr(code)
+	apiOp.OK = code == 200
+	if resp != nil {
+		if _, content := firstContent(resp.Content); content != nil {
+			if apiOp.Result, err = b.goType(handler+"Response", content.Schema); err != nil {
+				return nil, fmt.Errorf("response: %w", err)
+			}
+		}
+	}
+	return apiOp, nil
+}
+
+// parameters 解析参数引用，操作级参数覆盖同名同位置的路径级参数
+func (b *openapiBuilder) parameters(list []*openapiParameter) ([]*openapiParameter, error) {
+	var params []*openapiParameter
+	for _, p := range list {
+		if p.Ref != "" {
+			name, err := componentRefName(p.Ref, "parameters")
+			if err != nil {
+				return nil, err
+			}
+			resolved, ok := b.doc.Components.Parameters[name]
+			if !ok {
+				return nil, fmt.Errorf("parameter %s not found", p.Ref)
+			}
+			p = resolved
+		}
+		if p.In == "cookie" {
+			continue
+		}
+		if i := slices.IndexFunc(params, func(q *openapiParameter) bool { return q.Name == p.Name && q.In == p.In }); i >= 0 {
+			params[i] = p
+			continue
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func (b *openapiBuilder) requestBody(body *openapiRequestBody) (*openapiRequestBody, error) {
+	if body.Ref == "" {
+		return body, nil
+	}
+	name, err := componentRefName(body.Ref, "requestBodies")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := b.doc.Components.RequestBodies[name]
+	if !ok {
+		return nil, fmt.Errorf("request body %s not found", body.Ref)
+	}
+	return resolved, nil
+}
+
+// successResponse 返回状态码最小的 2xx 响应，未定义时视为 200
+func (b *openapiBuilder) successResponse(op *openapiOperation) (int, *openapiResponse, error) {
+	var codes []int
+	for _, key := range op.Responses.Keys {
+		if code, err := strconv.Atoi(key); err == nil && code >= 200 && code < 300 {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return 200, nil, nil
+	}
+	sort.Ints(codes)
+	resp := op.Responses.Values[strconv.Itoa(codes[0])]
+	if resp != nil && resp.Ref != "" {
+		name, err := componentRefName(resp.Ref, "responses")
+		if err != nil {
+			return 0, nil, err
+		}
+		resolved, ok := b.doc.Components.Responses[name]
+		if !ok {
+			return 0, nil, fmt.Errorf("response %s not found", resp.Ref)
+		}
+		resp = resolved
+	}
+	return codes[0], resp, nil
+}
+
+// firstContent 优先返回 JSON 媒体类型，否则返回第一个媒体类型
+func firstContent(content orderedMap[*openapiMediaType]) (string, *openapiMediaType) {
+	for _, mediaType := range content.Keys {
+		if strings.Contains(mediaType, "json") {
+			return mediaType, content.Values[mediaType]
+		}
+	}
+	if len(content.Keys) > 0 {
+		return content.Keys[0], content.Values[content.Keys[0]]
+	}
+	return "", nil
+}
+
+// httpStatusNames 是常见状态码对应的 net/http 常量名
+var httpStatusNames = map[int]string{
+	200: "StatusOK",
+	201: "StatusCreated",
+	202: "StatusAccepted",
+	203: "StatusNonAuthoritativeInfo",
+	204: "StatusNoContent",
+	205: "StatusResetContent",
+	206: "StatusPartialContent",
+	207: "StatusMultiStatus",
+	208: "StatusAlreadyReported",
+	226: "StatusIMUsed",
+}
+
+func statusExpr(code int) string {
+	if name, ok := httpStatusNames[code]; ok {
+		return "http." + name
+	}
+	return strconv.Itoa(code)
+}
+
+func schemaRefName(ref string) (string, error) {
+	return componentRefName(ref, "schemas")
+}
+
+// componentRefName 返回 #/components/<section>/<name> 形式引用中的名称，不支持外部引用
+func componentRefName(ref, section string) (string, error) {
+	prefix := "#/components/" + section + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("unsupported $ref %q (only %s* is supported)", ref, prefix)
+	}
+	name, err := url.PathUnescape(strings.TrimPrefix(ref, prefix))
+	if err != nil {
+		return "", err
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name), nil
+}
+
+// goTypeName 将 schema、参数或操作名称转为导出的 Go 标识符，如 user-profile → UserProfile
+func goTypeName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	var parts []string
+	for _, part := range strings.Split(utility.ToSnakeCase(sb.String()), "_") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	ident := fieldName(strings.Join(parts, "_"))
+	if ident == "" || unicode.IsDigit(rune(ident[0])) {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+// defaultHandlerName 为没有 operationId 的操作生成名称，如 GET /users/{id} → get_users_by_id
+func defaultHandlerName(method, path string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "":
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			parts = append(parts, "by", strings.Trim(segment, "{}"))
+		default:
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// ginRoute 将 /users/{id} 形式的路径转为 gin 的 /users/:id
+func ginRoute(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + strings.Trim(segment, "{}")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func firstPathSegment(path string) string {
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && !strings.HasPrefix(segment, "{") {
+			return segment
+		}
+	}
+	return ""
+}
+
+// commonRoutePrefix 返回路由路径共同的静态前缀，如 /users 与 /users/:id 的 /users
+func commonRoutePrefix(routes []string) string {
+	if len(routes) == 0 {
+		return ""
+	}
+	prefix := strings.Split(strings.Trim(routes[0], "/"), "/")
+	for _, route := range routes[1:] {
+		segments := strings.Split(strings.Trim(route, "/"), "/")
+		n := 0
+		for n < len(prefix) && n < len(segments) && prefix[n] == segments[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	for i, segment := range prefix {
+		if strings.HasPrefix(segment, ":") || segment == "" {
+			prefix = prefix[:i]
+			break
+		}
+	}
+	if len(prefix) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(prefix, "/")
+}
+
+// serverBasePath 返回第一个 server URL 的路径部分，如 https://api.example.com/v1 → /v1
+func (doc *openapiDoc) serverBasePath() string {
+	if len(doc.Servers) == 0 {
+		return ""
+	}
+	u, err := url.Parse(doc.Servers[0].URL)
+	if err != nil || strings.Contains(u.Path, "{") {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
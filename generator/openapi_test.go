@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testOpenAPI = `openapi: 3.1.0
+info: {title: Shop, version: "1.0"}
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      tags: [orders]
+      parameters:
+        - {name: page, in: query, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Order"}}
+  /orders/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string, format: uuid}}
+    put:
+      tags: [orders]
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                note: {type: [string, "null"]}
+      responses:
+        "202": {description: accepted}
+components:
+  schemas:
+    Order:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string}
+        total: {type: number, format: float}
+        created_at: {type: string, format: date-time}
+        meta: {type: object, additionalProperties: true}
+`
+
+func TestBuildOpenAPI(t *testing.T) {
+	doc, err := parseOpenAPI([]byte(testOpenAPI))
+	if err != nil {
+		t.Fatalf("parseOpenAPI failed: %v", err)
+	}
+	b, tags, operations, err := buildOpenAPI(doc)
+	if err != nil {
+		t.Fatalf("buildOpenAPI failed: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"orders"}) {
+		t.Fatalf("tags = %v; want [orders]", tags)
+	}
+
+	var names []string
+	for _, typ := range b.types {
+		names = append(names, typ.Name)
+	}
+	wantNames := []string{"Order", "ListOrdersQuery", "PutOrdersByIDPath", "PutOrdersByIDRequest"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("types = %v; want %v", names, wantNames)
+	}
+	wantFields := []apiField{
+		{Name: "ID", Type: "string", Tag: `json:"id" binding:"required"`},
+		{Name: "Total", Type: "float32", Tag: `json:"total,omitempty"`},
+		{Name: "CreatedAt", Type: "time.Time", Tag: `json:"created_at,omitempty"`},
+		{Name: "Meta", Type: "map[string]any", Tag: `json:"meta,omitempty"`},
+	}
+	if !reflect.DeepEqual(b.types[0].Fields, wantFields) {
+		t.Errorf("Order fields = %+v; want %+v", b.types[0].Fields, wantFields)
+	}
+	if got := b.types[3].Fields[0].Type; got != "*string" {
+		t.Errorf("nullable note type = %s; want *string", got)
+	}
+	if got := b.imports(); !reflect.DeepEqual(got, []string{"time"}) {
+		t.Errorf("imports = %v; want [time]", got)
+	}
+
+	ops := operations["orders"]
+	if len(ops) != 2 {
+		t.Fatalf("operations = %d; want 2", len(ops))
+	}
+	list := ops[0]
+	if list.Handler != "ListOrders" || list.Result != "[]Order" || !list.OK || list.Status != "http.StatusOK" {
+		t.Errorf("listOrders = %+v", list)
+	}
+	put := ops[1]
+	wantParams := []apiBinding{
+		{Var: "path", Type: "PutOrdersByIDPath", Method: "ShouldBindUri"},
+		{Var: "req", Type: "PutOrdersByIDRequest", Method: "ShouldBind"},
+	}
+	if put.Route != "/orders/:id" || put.Status != "http.StatusAccepted" || put.OK || put.Result != "" || !reflect.DeepEqual(put.Params, wantParams) {
+		t.Errorf("put = %+v", put)
+	}
+
+	group, routes := operationRoutes("/api", ops)
+	if group != "/api/orders" || routes["ListOrders"] != [2]string{"GET", ""} || routes["PutOrdersByID"] != [2]string{"PUT", "/:id"} {
+		t.Errorf("operationRoutes = %s %v", group, routes)
+	}
+}
+
+func TestParseOpenAPIErrors(t *testing.T) {
+	for _, src := range []string{
+		"swagger: \"2.0\"\n",
+		"info: {title: x}\n",
+	} {
+		if _, err := parseOpenAPI([]byte(src)); err == nil {
+			t.Errorf("parseOpenAPI(%q) expected error", src)
+		}
+	}
+
+	doc, _ := parseOpenAPI([]byte("openapi: 3.0.0\npaths:\n  /x:\n    get:\n      responses:\n        '200':\n          content:\n            application/json:\n              schema: {$ref: 'other.yaml#/X'}\n"))
+	if _, _, _, err := buildOpenAPI(doc); err == nil {
+		t.Error("expected error for external $ref")
+	}
+}
+
+func TestGoTypeName(t *testing.T) {
+	tests := map[string]string{
+		"user":           "User",
+		"user-profile":   "UserProfile",
+		"getUserById":    "GetUserByID",
+		"X-Request-ID":   "XRequestID",
+		"v1.Order":       "V1Order",
+		"2fa_code":       "X2FaCode",
+		"get_pets_by_id": "GetPetsByID",
+	}
+	for in, want := range tests {
+		if got := goTypeName(in); got != want {
+			t.Errorf("goTypeName(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestCommonRoutePrefix(t *testing.T) {
+	tests := []struct {
+		routes []string
+		want   string
+	}{
+		{[]string{"/users", "/users/:id"}, "/users"},
+		{[]string{"/users/:id/posts", "/users/:id"}, "/users"},
+		{[]string{"/users", "/orders"}, ""},
+		{[]string{"/:id"}, ""},
+	}
+	for _, tt := range tests {
+		if got := commonRoutePrefix(tt.routes); got != tt.want {
+			t.Errorf("commonRoutePrefix(%v) = %q; want %q", tt.routes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/spf13/cobra"
+)
+
+// 生成文件时实际（或在 --dry-run 下将要）执行的操作
+const (
+	actionCreated     = "created"
+	actionOverwritten = "overwritten"
+	actionSkipped     = "skipped"
+	actionMerged      = "merged"
+)
+
+// addGenerateFlags 为生成命令注册控制输出方式的公共 flag
+func addGenerateFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("with-test", false, "also generate a <name>_test.go file (default from with-test in .gouno.yaml)")
+	cmd.Flags().Bool("dry-run", false, "report the files that would be created or overwritten without writing them")
+	cmd.Flags().Bool("diff", false, "show a unified diff against existing files without writing them (implies --dry-run)")
+	cmd.Flags().Bool("merge", false, "three-way merge into existing files, keeping manual edits")
+	cmd.Flags().StringArray("var", nil, "set a template set variable as name=value (repeatable)")
+	cmd.Flags().Bool("no-hooks", false, "skip the post-generation hooks declared in .gouno.yaml and the template set")
+	cmd.Flags().Bool("trust-hooks", false, "run the template set's hooks even if they are not listed in trusted-hooks in .gouno.yaml")
+}
+
+// generatedCodeMarker 匹配 Go 约定的生成代码标记行 "// Code generated ... DO NOT EDIT."
+var generatedCodeMarker = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// isGeneratedCode 判断文件是否为不应手动编辑的生成代码
+func isGeneratedCode(content []byte) bool {
+	return generatedCodeMarker.Match(content)
+}
+
+// isDryRun 判断是否只报告而不写入文件
+func isDryRun(cmd *cobra.Command) bool {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	diff, _ := cmd.Flags().GetBool("diff")
+	return dryRun || diff
+}
+
+// writeFile 将渲染结果写入目标文件并返回执行的操作：
+// 1. 文件已存在且未指定 --force / --merge 时跳过（gouno 自己生成、带有 DO NOT EDIT 标记的文件直接覆盖）
+// 2. 指定 --merge 时以 .gouno/cache 中记录的上次生成结果为基准进行三方合并
+// 3. 指定 --dry-run 时只报告将要执行的操作，--diff 时额外输出与现有内容的统一 diff
+// 每次实际写入后都会记录本次的原始生成结果，供之后合并使用
+func writeFile(cmd *cobra.Command, typeName, filePath, content string) (string, error) {
+	existing, err := os.ReadFile(filePath)
+	exists := err == nil
+	force, _ := cmd.Flags().GetBool("force")
+	merge, _ := cmd.Flags().GetBool("merge")
+
+	action := actionCreated
+	switch {
+	case exists && merge:
+		action = actionMerged
+	case exists && !force && !(isGeneratedCode(existing) && generatedByGouno(filePath)):
+		action = actionSkipped
+	case exists:
+		action = actionOverwritten
+	}
+	return writeFileAs(cmd, typeName, filePath, content, action)
+}
+
+// generatedByGouno 判断文件是否由 gouno 生成：记录在 .gouno.lock 中，或在 .gouno/cache 中保存了生成时的输出
+// 其他工具（如 protoc、stringer）生成的文件即使带有 DO NOT EDIT 标记也不会被覆盖
+func generatedByGouno(filePath string) bool {
+	if _, ok := loadPristine(filePath); ok {
+		return true
+	}
+	lock, err := loadLock()
+	key := lockKey(filePath)
+	return err == nil && key != "" && lock.Files[key] != nil
+}
+
+// writeFileAs 与 writeFile 相同，但由调用方决定执行的操作（actionCreated、actionOverwritten 等）
+func writeFileAs(cmd *cobra.Command, typeName, filePath, content, action string) (result string, err error) {
+	defer func() {
+		if err == nil {
+			currentSession.actions[result]++
+		}
+	}()
+	existing, err := os.ReadFile(filePath)
+	exists := err == nil
+
+	output := content
+	conflicts := 0
+	if action == actionMerged {
+		base, ok := loadPristine(filePath)
+		if !ok {
+			cmd.Printf("No generated output recorded for %s file, skipping merge: %s (use --force to overwrite)\n", typeName, filePath)
+			return actionSkipped, nil
+		}
+		output, conflicts = mergeLines(base, string(existing), content)
+	}
+
+	if isDryRun(cmd) {
+		switch action {
+		case actionCreated:
+			cmd.Printf("Would create %s file: %s\n", typeName, filePath)
+		case actionOverwritten:
+			cmd.Printf("Would overwrite %s file: %s\n", typeName, filePath)
+		case actionMerged:
+			cmd.Printf("Would merge %s file: %s%s\n", typeName, filePath, conflictNote(conflicts))
+		default:
+			cmd.Printf("Would skip existing %s file: %s (use --force to overwrite)\n", typeName, filePath)
+		}
+		printDiff(cmd, filePath, string(existing), output, exists)
+		return action, nil
+	}
+
+	if action == actionSkipped {
+		cmd.Printf("%s file already exists, skipping: %s (use --force to overwrite)\n", typeName, filePath)
+		return action, nil
+	}
+
+	dir := filepath.Dir(filePath)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		currentSession.tx.recordDir(dir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create %s directory: %w", typeName, err)
+		}
+		cmd.Printf("Created directory: %s\n", dir)
+	}
+	currentSession.tx.recordFile(filePath)
+	if err := os.WriteFile(filePath, []byte(output), 0644); err != nil {
+		return "", fmt.Errorf("failed to create %s file: %w", typeName, err)
+	}
+	if err := savePristine(filePath, content); err != nil {
+		return "", err
+	}
+	recordWritten(typeName, filePath)
+	switch action {
+	case actionOverwritten:
+		cmd.Printf("Overwrote %s file: %s\n", typeName, filePath)
+	case actionMerged:
+		cmd.Printf("Merged %s file: %s%s\n", typeName, filePath, conflictNote(conflicts))
+	default:
+		cmd.Printf("Created %s file: %s\n", typeName, filePath)
+	}
+	return action, nil
+}
+
+// conflictNote 返回合并冲突数量的提示
+func conflictNote(conflicts int) string {
+	if conflicts == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d conflict(s), resolve the %q markers manually)", conflicts, conflictStart)
+}
+
+// printDiff 在指定 --diff 时输出文件现有内容到新内容的统一 diff
+func printDiff(cmd *cobra.Command, filePath, oldContent, newContent string, exists bool) {
+	if diff, _ := cmd.Flags().GetBool("diff"); !diff {
+		return
+	}
+	oldName := ""
+	if exists {
+		oldName = filePath
+	}
+	if d := unifiedDiff(oldName, filePath, oldContent, newContent); d != "" {
+		cmd.Print(d)
+	} else {
+		cmd.Println("(no changes)")
+	}
+}
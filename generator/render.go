@@ -0,0 +1,252 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rushairer/gouno/utility"
+)
+
+// templateData 是渲染模板时可引用的数据模型，模板中通过 {{.StructName}} 等方式访问：
+//
+//	.Name        命令行传入的原始名称，如 foo_bar、admin/user
+//	.Group       名称中最后一个 / 之前的分组，如 admin（没有分组时为空），文件生成在各层目录的同名子目录中
+//	.StructName  驼峰命名，如 FooBar；嵌套名称包含分组，如 AdminUser
+//	.Snake       蛇形命名，如 foo_bar
+//	.Package     目标目录对应的包名，如 service
+//	.Module      当前项目 go.mod 中声明的模块路径（不存在时为空）
+//	.TemplateSet 当前使用的模板集名称
+//	.Timestamp   生成时间
+//	.Fields      命令行传入的字段定义，每项包含 .Name .Snake .Type .Options .Tag .Param
+//	.Imports     字段类型需要引入的包
+//	.ID          名为 id 的字段（不存在时为 nil）
+//	.Packages    各层默认目录（嵌套名称为其中的分组子目录）的导入路径，如 {{index .Packages "domain"}}
+//	.Import      各层的导入声明，包名与层名不同时带别名，如 {{.Import "domain"}}
+//	.Mock        被模拟类型的方法集，仅 mock 模板中可用（其余为 nil）
+//	.API         OpenAPI 文档生成的类型与操作，仅 openapi_* 模板中可用（其余为 nil）
+//	.Vars        模板集变量的取值，如 {{.Vars.author}}，见 TemplateVariable
+type templateData struct {
+	Name        string
+	Group       string
+	StructName  string
+	Snake       string
+	Package     string
+	Module      string
+	TemplateSet string
+	Timestamp   time.Time
+	Fields      []field
+	Imports     []string
+	ID          *field
+	Packages    map[string]string
+	Mock        *mockSpec
+	API         *apiSpec
+	Vars        map[string]string
+
+	args []string // 生成命令的名称与字段定义，记录在 .gouno.lock 中以便重新渲染
+}
+
+// Import 返回 typeName 层的导入声明，如 "example.com/app/internal/domain"；
+// 包名与层名不同（如嵌套名称生成在 internal/domain/admin 中）时以层名为别名，使模板可以始终使用 domain. 引用
+func (d *templateData) Import(typeName string) string {
+	importPath := d.Packages[typeName]
+	if packageName(importPath, typeName) == typeName {
+		return strconv.Quote(importPath)
+	}
+	return typeName + " " + strconv.Quote(importPath)
+}
+
+// templateFuncs 是模板中可用的函数集合
+var templateFuncs = template.FuncMap{
+	"camel":      utility.ToCamelCase,
+	"snake":      utility.ToSnakeCase,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"lowerFirst": lowerFirst,
+	"params":     fieldParams,
+	"comment":    comment,
+}
+
+// renderTemplate 使用 text/template 渲染模板
+// 不含 {{ 但含 %s 的旧式模板仍按 fmt.Sprintf 方式渲染，保持兼容
+func renderTemplate(name, tmpl string, data *templateData) (string, error) {
+	if isLegacyTemplate(tmpl) {
+		count, err := legacyVerbCount(tmpl)
+		if err != nil {
+			return "", fmt.Errorf("failed to render %s template: %w", name, err)
+		}
+		args := make([]any, count)
+		for i := range args {
+			args[i] = data.StructName
+		}
+		return fmt.Sprintf(tmpl, args...), nil
+	}
+
+	t, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// isLegacyTemplate 判断模板是否为旧式的 fmt.Sprintf 位置参数模板
+func isLegacyTemplate(tmpl string) bool {
+	return !strings.Contains(tmpl, "{{") && strings.Contains(tmpl, "%s")
+}
+
+// legacyVerbCount 返回旧式模板中 %s 动词的个数，每个 %s 都替换为结构体名称；
+// %% 是字面的百分号，其他动词（如 %d、%v 或 %[1]s）没有对应的参数，返回错误而不是渲染出 %!d(...) 之类的内容
+func legacyVerbCount(tmpl string) (int, error) {
+	count := 0
+	for i := 0; i < len(tmpl); i++ {
+		if tmpl[i] != '%' {
+			continue
+		}
+		start := i
+		// 跳过标志、宽度与精度
+		for i++; i < len(tmpl) && strings.IndexByte("+-# 0123456789.", tmpl[i]) >= 0; i++ {
+		}
+		switch {
+		case i == len(tmpl):
+			return 0, fmt.Errorf("line %d: incomplete verb %q at end of legacy template (write %%%% for a literal percent sign)",
+				strings.Count(tmpl[:start], "\n")+1, tmpl[start:])
+		case tmpl[i] == '%' && i == start+1:
+		case tmpl[i] == 's':
+			count++
+		default:
+			verb, _ := utf8.DecodeRuneInString(tmpl[i:])
+			return 0, fmt.Errorf("line %d: unsupported verb %q in legacy template: only %%s is replaced with the struct name (write %%%% for a literal percent sign)",
+				strings.Count(tmpl[:start], "\n")+1, tmpl[start:i]+string(verb))
+		}
+	}
+	return count, nil
+}
+
+// layerImports 返回各层目录对应的导入路径，模块路径为空时返回空表
+// 模板集清单中声明了类型的默认目录时使用清单中的目录，自定义类型同样包含在内；
+// 生成套件时使用套件成员的目录；嵌套名称的 group 为各层目录中的子目录
+func layerImports(module string, manifest *TemplateManifest, group string) map[string]string {
+	imports := make(map[string]string)
+	if module == "" {
+		return imports
+	}
+	for typeName, dir := range map[string]string{
+		"domain":     defaultDomainPath,
+		"repository": defaultRepositoryPath,
+		"service":    defaultServicePath,
+		"controller": defaultControllerPath,
+		"task":       defaultTaskPath,
+	} {
+		imports[typeName] = path.Join(module, filepath.ToSlash(cmp.Or(currentSession.paths[typeName], manifest.path(typeName), dir)), group)
+	}
+	if manifest != nil {
+		for name := range manifest.Kinds {
+			imports[name] = path.Join(module, filepath.ToSlash(cmp.Or(currentSession.paths[name], manifest.path(name), defaultKindPath(name))), group)
+		}
+	}
+	return imports
+}
+
+// dirImportPath 返回项目内目录对应的导入路径，目录不在项目内或模块路径为空时返回空字符串
+func dirImportPath(module, projectRoot, dir string) string {
+	if module == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(projectRoot, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return path.Join(module, filepath.ToSlash(rel))
+}
+
+// splitName 将名称拆分为分组与最后一段，如 admin/user 拆分为 admin 与 user，分组可以有多级
+func splitName(name string) (group, base string) {
+	name = strings.Trim(filepath.ToSlash(name), "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return path.Clean(name[:i]), name[i+1:]
+	}
+	return "", name
+}
+
+// structName 返回名称对应的驼峰命名，嵌套名称默认包含分组（admin/user 为 AdminUser），
+// .gouno.yaml 中设置 short-nested-names 时只使用最后一段（User）
+func structName(name string) string {
+	if cfg := loadProjectConfig(); cfg != nil && cfg.ShortNestedNames {
+		_, base := splitName(name)
+		return utility.ToCamelCase(base)
+	}
+	return qualifiedStructName(name)
+}
+
+// qualifiedStructName 返回包含分组的驼峰命名，如 admin/user 为 AdminUser
+func qualifiedStructName(name string) string {
+	group, base := splitName(name)
+	if group != "" {
+		base = strings.ReplaceAll(group, "/", "_") + "_" + base
+	}
+	return utility.ToCamelCase(base)
+}
+
+// packageName 根据目标目录推导包名，无法推导时回退为类型名
+func packageName(dir, typeName string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(dir))
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return typeName
+	}
+	return name
+}
+
+// readModulePath 读取项目根目录下 go.mod 中声明的模块路径
+func readModulePath(projectRoot string) string {
+	f, err := os.Open(filepath.Join(projectRoot, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// comment 将文本转为 Go 行注释，多行文本逐行添加 "// "
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+strings.TrimSpace(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
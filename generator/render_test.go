@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := &templateData{
+		Name:        "foo_bar",
+		StructName:  "FooBar",
+		Snake:       "foo_bar",
+		Package:     "service",
+		Module:      "example.com/app",
+		TemplateSet: "default",
+	}
+
+	t.Run("text/template", func(t *testing.T) {
+		got, err := renderTemplate("service", "package {{.Package}} // {{.Module}}\ntype {{.StructName}} struct{} // {{lowerFirst .StructName}} {{.Snake | upper}}", data)
+		if err != nil {
+			t.Fatalf("renderTemplate failed: %v", err)
+		}
+		want := "package service // example.com/app\ntype FooBar struct{} // fooBar FOO_BAR"
+		if got != want {
+			t.Errorf("renderTemplate = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("legacy printf", func(t *testing.T) {
+		got, err := renderTemplate("service", "type %s struct{}\nfunc New%s() *%s { return &%s{} }", data)
+		if err != nil {
+			t.Fatalf("renderTemplate failed: %v", err)
+		}
+		want := "type FooBar struct{}\nfunc NewFooBar() *FooBar { return &FooBar{} }"
+		if got != want {
+			t.Errorf("renderTemplate = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("legacy printf literal percent and flags", func(t *testing.T) {
+		got, err := renderTemplate("service", "type %s struct{} // 100%% %-8s|\nvar _ = fmt.Sprintf(\"%%s\")", data)
+		if err != nil {
+			t.Fatalf("renderTemplate failed: %v", err)
+		}
+		want := "type FooBar struct{} // 100% FooBar  |\nvar _ = fmt.Sprintf(\"%s\")"
+		if got != want {
+			t.Errorf("renderTemplate = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("legacy printf unsupported verbs", func(t *testing.T) {
+		for tmpl, want := range map[string]string{
+			"type %s struct{}\nvar n = %d": `line 2: unsupported verb "%d"`,
+			"type %s struct{} // %v":       `line 1: unsupported verb "%v"`,
+			"type %s struct{} // %q":       `unsupported verb "%q"`,
+			"type %s struct{} // %[1]s":    `unsupported verb "%["`,
+			"type %s struct{} // 100%":     `incomplete verb "%"`,
+		} {
+			_, err := renderTemplate("service", tmpl, data)
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("renderTemplate(%q) error = %v; want %q", tmpl, err, want)
+			}
+		}
+	})
+
+	t.Run("comment", func(t *testing.T) {
+		got, err := renderTemplate("service", "{{comment \"First line.\\n\\n  Second line. \"}}", data)
+		if err != nil {
+			t.Fatalf("renderTemplate failed: %v", err)
+		}
+		want := "// First line.\n//\n// Second line."
+		if got != want {
+			t.Errorf("renderTemplate = %q; want %q", got, want)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		if _, err := renderTemplate("service", "{{.Missing}}", data); err == nil {
+			t.Fatal("expected error for unknown field")
+		}
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		if _, err := renderTemplate("service", "{{.StructName", data); err == nil {
+			t.Fatal("expected parse error")
+		}
+	})
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{filepath.Join("internal", "service"), "service"},
+		{filepath.Join("custom", "My-Repo"), "myrepo"},
+		{filepath.Join("api", "v1"), "v1"},
+		{filepath.Join("pkg", "1st"), "domain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			if got := packageName(tt.dir, "domain"); got != tt.expected {
+				t.Errorf("packageName(%q) = %q; want %q", tt.dir, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadModulePath(t *testing.T) {
+	dir := t.TempDir()
+	if got := readModulePath(dir); got != "" {
+		t.Errorf("readModulePath without go.mod = %q; want empty", got)
+	}
+
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("// comment\nmodule example.com/app\n\ngo 1.23\n"), 0644)
+	if got := readModulePath(dir); got != "example.com/app" {
+		t.Errorf("readModulePath = %q; want example.com/app", got)
+	}
+}
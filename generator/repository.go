@@ -1,19 +1,28 @@
 package generator
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 )
 
 var repositoryCmd = &cobra.Command{
-	Use:                   "repository [name]",
+	Use:                   "repository [name] [field:type[:option]...]",
 	Short:                 "Generate repository",
 	Aliases:               []string{"r"},
-	Args:                  cobra.ExactArgs(1),
+	Args:                  cobra.MinimumNArgs(1),
 	DisableFlagsInUseLine: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return generateFile(cmd, args, "repository", defaultRepositoryPath)
+		file, err := generateTemplateFile(cmd, args, "repository", "repository", defaultRepositoryPath)
+		if err != nil {
+			return err
+		}
+		if mock, _ := cmd.Flags().GetBool("mock"); mock {
+			return generateRepositoryMock(cmd, file)
+		}
+		return nil
 	},
 }
 
@@ -23,4 +32,28 @@ func init() {
 	repositoryCmd.Flags().StringP("path", "p", defaultRepositoryPath, "path to repository")
 	repositoryCmd.Flags().BoolP("force", "f", false, "force overwrite")
 	repositoryCmd.Flags().String("template-set", "", "template set name")
+	repositoryCmd.Flags().Bool("mock", false, "also generate a fake implementation in <path>/mock")
+	addGenerateFlags(repositoryCmd)
+}
+
+// generateRepositoryMock 为刚生成的 repository 生成 fake
+// --dry-run 时 repository 尚未写入磁盘，以渲染结果代替磁盘内容进行类型检查
+func generateRepositoryMock(cmd *cobra.Command, file *generatedFile) error {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	sourcePath, err := filepath.Rel(projectRoot, filepath.Dir(file.Path))
+	if err != nil {
+		return err
+	}
+	var overlay map[string]string
+	if isDryRun(cmd) {
+		overlay = make(map[string]string)
+		for _, f := range file.files() {
+			overlay[f.Path] = f.Content
+		}
+	}
+	_, err = generateMock(cmd, file.Data.Name, "repository", sourcePath, filepath.Join(sourcePath, mockDirName), overlay)
+	return err
 }
@@ -0,0 +1,684 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rushairer/gouno/utility"
+	"github.com/spf13/cobra"
+)
+
+const ginImportPath = "github.com/gin-gonic/gin"
+
+// RouterConfig 路由文件配置，用于生成控制器后自动注册路由
+//
+//	router:
+//	  file: router/router.go
+//	  func: RegisterRoutes
+type RouterConfig struct {
+	File string `yaml:"file"` // 路由文件路径，相对于项目根目录
+	Func string `yaml:"func"` // 注册路由的函数名，为空时使用第一个接收 *gin.Engine 或 *gin.RouterGroup 的函数
+}
+
+// routeRegistration 描述需要注册到路由文件中的控制器
+type routeRegistration struct {
+	Controller  *generatedFile       // 生成的控制器文件
+	Constructor string               // 控制器构造表达式，如 controller.NewAuthController()
+	Imports     []string             // 构造表达式需要的导入路径
+	Names       map[string]string    // 需要以别名导入的路径及其别名
+	Group       string               // 路由分组路径，为空时使用 /<snake>
+	Routes      map[string][2]string // 处理函数对应的 HTTP 方法与分组内路径，未列出的按 handlerRoutes 推断
+}
+
+// handlerRoutes 是常见处理函数名对应的 HTTP 方法与路径
+var handlerRoutes = map[string][2]string{
+	"List":   {"GET", ""},
+	"Get":    {"GET", "/:id"},
+	"Create": {"POST", ""},
+	"Update": {"PUT", "/:id"},
+	"Delete": {"DELETE", "/:id"},
+}
+
+// newRouteRegistration 为没有构造参数的控制器创建路由注册信息
+func newRouteRegistration(controller *generatedFile) *routeRegistration {
+	reg := &routeRegistration{Controller: controller}
+	reg.Constructor = fmt.Sprintf("%s.New%sController()", reg.importFile(controller), controller.Data.StructName)
+	return reg
+}
+
+// importFile 将生成文件所在的包加入导入，返回路由文件中引用该包的名称
+// 嵌套名称（如 admin/user）各层的包名都是分组名，因此以 <包名><类型> 为别名（如 admincontroller）避免冲突
+func (r *routeRegistration) importFile(file *generatedFile) string {
+	r.Imports = append(r.Imports, file.ImportPath)
+	if file.Data.Group == "" {
+		return file.Data.Package
+	}
+	name := file.Data.Package + file.TypeName
+	if r.Names == nil {
+		r.Names = make(map[string]string)
+	}
+	r.Names[file.ImportPath] = name
+	return name
+}
+
+// routeGroup 返回控制器的路由分组路径，嵌套名称按分组嵌套，如 admin/user 为 /admin/user
+func routeGroup(data *templateData) string {
+	if data.Group == "" {
+		return "/" + data.Snake
+	}
+	_, base := splitName(data.Name)
+	return "/" + path.Join(data.Group, utility.ToSnakeCase(utility.ToCamelCase(base)))
+}
+
+// routeVar 返回路由函数中控制器变量名的前缀，嵌套名称始终包含分组以免与同名资源冲突
+func routeVar(data *templateData) string {
+	if data.Group == "" {
+		return lowerFirst(data.StructName)
+	}
+	return lowerFirst(qualifiedStructName(data.Name))
+}
+
+// registerRoute 将控制器的构造调用与路由分组注册写入 .gouno.yaml 中配置的路由文件
+// 未配置路由文件或指定 --no-route 时不做任何修改；已注册过的控制器会被跳过
+func registerRoute(cmd *cobra.Command, reg *routeRegistration) error {
+	if noRoute, _ := cmd.Flags().GetBool("no-route"); noRoute {
+		return nil
+	}
+	cfg := loadProjectConfig()
+	if cfg == nil || cfg.Router.File == "" {
+		return nil
+	}
+	if reg.Controller.ImportPath == "" {
+		return fmt.Errorf("route registration requires a go.mod to resolve the controller import path")
+	}
+
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	routerPath := filepath.Join(projectRoot, cfg.Router.File)
+	src, err := os.ReadFile(routerPath)
+	if err != nil {
+		return fmt.Errorf("failed to read router file: %w", err)
+	}
+
+	// 目录模板生成的多个文件中的处理函数一并注册
+	var handlers []string
+	for _, file := range reg.Controller.files() {
+		if !strings.HasSuffix(file.Path, ".go") || strings.HasSuffix(file.Path, "_test.go") {
+			continue
+		}
+		// 新建或覆盖时使用（将要）写入的内容，跳过或合并时以磁盘上的控制器为准
+		var controllerSrc any
+		if file.Action == actionCreated || file.Action == actionOverwritten {
+			controllerSrc = file.Content
+		}
+		fileHandlers, err := controllerHandlers(file.Path, controllerSrc, reg.Controller.Data.StructName+"Controller")
+		if err != nil {
+			return err
+		}
+		handlers = append(handlers, fileHandlers...)
+	}
+
+	updated, added, err := insertRoute(routerPath, src, cfg.Router.Func, reg, handlers)
+	if err != nil {
+		return fmt.Errorf("failed to update router file %s: %w", routerPath, err)
+	}
+	if added == nil {
+		cmd.Printf("Route already registered, skipping: %s\n", routerPath)
+		return nil
+	}
+	if isDryRun(cmd) {
+		cmd.Printf("Would update router file: %s\n", routerPath)
+	} else {
+		currentSession.tx.recordFile(routerPath)
+		if err := os.WriteFile(routerPath, updated, 0644); err != nil {
+			return fmt.Errorf("failed to write router file: %w", err)
+		}
+		recordWritten("router", routerPath)
+		cmd.Printf("Updated router file: %s\n", routerPath)
+	}
+	for _, line := range added {
+		cmd.Printf("  + %s\n", line)
+	}
+	printDiff(cmd, routerPath, string(src), string(updated), true)
+	return nil
+}
+
+// unregisterRoute 从 .gouno.yaml 中配置的路由文件移除控制器的构造调用与路由分组（registerRoute 的逆操作），
+// 并删除不再使用的导入；未配置路由文件、指定 --no-route 或控制器未注册时不做任何修改
+func unregisterRoute(cmd *cobra.Command, data *templateData, importPath string) error {
+	if noRoute, _ := cmd.Flags().GetBool("no-route"); noRoute {
+		return nil
+	}
+	cfg := loadProjectConfig()
+	if cfg == nil || cfg.Router.File == "" {
+		return nil
+	}
+
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	routerPath := filepath.Join(projectRoot, cfg.Router.File)
+	src, err := os.ReadFile(routerPath)
+	if err != nil {
+		return fmt.Errorf("failed to read router file: %w", err)
+	}
+	updated, removed, err := removeRoute(routerPath, src, cfg.Router.Func, data.StructName, importPath, data.Module)
+	if err != nil {
+		return fmt.Errorf("failed to update router file %s: %w", routerPath, err)
+	}
+	if removed == nil {
+		return nil
+	}
+	if isDryRun(cmd) {
+		cmd.Printf("Would update router file: %s\n", routerPath)
+	} else {
+		currentSession.tx.recordFile(routerPath)
+		if err := os.WriteFile(routerPath, updated, 0644); err != nil {
+			return fmt.Errorf("failed to write router file: %w", err)
+		}
+		cmd.Printf("Updated router file: %s\n", routerPath)
+	}
+	for _, line := range removed {
+		cmd.Printf("  - %s\n", line)
+	}
+	return nil
+}
+
+// removeRoute 从路由函数中移除控制器的构造调用、引用控制器变量的语句以及只剩定义的路由分组变量，
+// 并删除因此不再使用的导入，返回格式化后的源码与移除的行；控制器按 importPath 包中的构造函数识别，
+// 路由函数中没有该控制器时返回 nil
+func removeRoute(filename string, src []byte, funcName, structName, importPath, module string) ([]byte, []string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	fn, _ := findRouterFunc(file, funcName)
+	if fn == nil {
+		return nil, nil, nil
+	}
+
+	stmts := fn.Body.List
+	removed := make(map[ast.Stmt]bool)
+	vars := make(map[string]bool)
+	for _, stmt := range stmts {
+		if callsConstructor(stmt, file, importPath, "New"+structName+"Controller") {
+			removed[stmt] = true
+			maps.Copy(vars, definedNames(stmt))
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil, nil
+	}
+	// 引用已移除变量的语句同样移除，直到不再变化
+	for changed := true; changed; {
+		changed = false
+		for _, stmt := range stmts {
+			if !removed[stmt] && referencesAny(stmt, vars, nil) {
+				removed[stmt], changed = true, true
+				maps.Copy(vars, definedNames(stmt))
+			}
+		}
+	}
+	// 被移除语句使用、此后只剩定义的变量（如 userGroup := api.Group("/user")）一并移除
+	used := make(map[string]bool)
+	for stmt := range removed {
+		maps.Copy(used, referencedNames(stmt))
+	}
+	for _, stmt := range stmts {
+		defined := definedNames(stmt)
+		if removed[stmt] || len(defined) == 0 {
+			continue
+		}
+		definesUsed := true
+		for name := range defined {
+			definesUsed = definesUsed && used[name]
+		}
+		if definesUsed && !referencesAny(fn.Body, defined, func(n ast.Stmt) bool { return n == stmt || removed[n] }) {
+			removed[stmt] = true
+		}
+	}
+
+	var edits []textEdit
+	var lines []string
+	for i, stmt := range stmts {
+		if !removed[stmt] {
+			continue
+		}
+		edit := lineEdit(fset, src, stmt)
+		// 函数体末尾的语句被移除时一并移除其前的空行，避免在 } 前留下空行
+		if !slices.ContainsFunc(stmts[i+1:], func(s ast.Stmt) bool { return !removed[s] }) &&
+			(i == 0 || !removed[stmts[i-1]]) && edit.Offset >= 2 && string(src[edit.Offset-2:edit.Offset]) == "\n\n" {
+			edit.Offset--
+			edit.Delete++
+		}
+		edits = append(edits, edit)
+		lines = append(lines, string(src[fset.Position(stmt.Pos()).Offset:fset.Position(stmt.End()).Offset]))
+	}
+	// 被移除语句引用、文件中不再使用的导入
+	isImport := func(decl ast.Decl) bool {
+		gen, ok := decl.(*ast.GenDecl)
+		return ok && gen.Tok == token.IMPORT
+	}
+	importUsed := func(name string) bool {
+		return slices.ContainsFunc(file.Decls, func(decl ast.Decl) bool {
+			return !isImport(decl) && referencesAny(decl, map[string]bool{name: true}, func(n ast.Stmt) bool { return removed[n] })
+		})
+	}
+	for _, decl := range file.Decls {
+		if !isImport(decl) {
+			continue
+		}
+		gen := decl.(*ast.GenDecl)
+		var unused []ast.Spec
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.ImportSpec)
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if name := importName(file, path); used[name] && !importUsed(name) {
+				unused = append(unused, spec)
+				lines = append(lines, string(src[fset.Position(spec.Pos()).Offset:fset.Position(spec.End()).Offset]))
+			}
+		}
+		if len(unused) == len(gen.Specs) && len(unused) > 0 {
+			edits = append(edits, lineEdit(fset, src, gen))
+			continue
+		}
+		for _, spec := range unused {
+			edits = append(edits, lineEdit(fset, src, spec))
+		}
+	}
+
+	out, err := formatGoSource(filename, applyEdits(src, edits), module)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, lines, nil
+}
+
+// definedNames 返回语句中以 := 定义的变量名
+func definedNames(stmt ast.Stmt) map[string]bool {
+	names := make(map[string]bool)
+	if assign, ok := stmt.(*ast.AssignStmt); ok && assign.Tok == token.DEFINE {
+		for _, lhs := range assign.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && ident.Name != "_" {
+				names[ident.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// referencedNames 返回节点中引用的标识符（不含选择器的字段名与 := 左侧的定义）
+func referencedNames(node ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(n.X, func(x ast.Node) bool {
+				if ident, ok := x.(*ast.Ident); ok {
+					names[ident.Name] = true
+				}
+				return true
+			})
+			return false
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, rhs := range n.Rhs {
+					maps.Copy(names, referencedNames(rhs))
+				}
+				return false
+			}
+		case *ast.Ident:
+			names[n.Name] = true
+		}
+		return true
+	})
+	return names
+}
+
+// referencesAny 判断节点中是否引用了 names 中的标识符，skip 返回 true 的语句不检查
+func referencesAny(node ast.Node, names map[string]bool, skip func(ast.Stmt) bool) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		if stmt, ok := n.(ast.Stmt); ok && skip != nil && skip(stmt) {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			found = referencesAny(n.X, names, skip)
+			return false
+		case *ast.Ident:
+			found = names[n.Name]
+		}
+		return true
+	})
+	return found
+}
+
+// insertRoute 在路由函数中插入控制器注册语句并补充缺失的导入，返回格式化后的源码与新增的行
+// 路由函数中已存在该控制器的构造调用时返回 nil
+func insertRoute(filename string, src []byte, funcName string, reg *routeRegistration, handlers []string) ([]byte, []string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fn, router := findRouterFunc(file, funcName)
+	if fn == nil {
+		if funcName != "" {
+			return nil, nil, fmt.Errorf("function %s not found", funcName)
+		}
+		return nil, nil, fmt.Errorf("no function accepting *gin.Engine or *gin.RouterGroup found")
+	}
+	if router == "" {
+		return nil, nil, fmt.Errorf("function %s has no gin router parameter or variable", fn.Name.Name)
+	}
+
+	constructor := "New" + reg.Controller.Data.StructName + "Controller"
+	if callsConstructor(fn.Body, file, reg.Controller.ImportPath, constructor) {
+		return nil, nil, nil
+	}
+
+	// 变量名已被其他控制器使用时（如不同分组中的同名控制器）添加序号
+	defined := make(map[string]bool)
+	for _, stmt := range fn.Body.List {
+		maps.Copy(defined, definedNames(stmt))
+	}
+	varName := routeVar(reg.Controller.Data)
+	for i, base := 2, varName; defined[varName+"Controller"] || defined[varName+"Group"]; i++ {
+		varName = base + strconv.Itoa(i)
+	}
+	stmts := []string{
+		fmt.Sprintf("%sController := %s", varName, reg.Constructor),
+		fmt.Sprintf("%sGroup := %s.Group(%q)", varName, router, cmp.Or(reg.Group, routeGroup(reg.Controller.Data))),
+	}
+	for _, handler := range handlers {
+		method, path := "GET", "/"+utility.ToSnakeCase(handler)
+		if route, ok := reg.Routes[handler]; ok {
+			method, path = route[0], route[1]
+		} else if route, ok := handlerRoutes[handler]; ok {
+			method, path = route[0], route[1]
+		}
+		stmts = append(stmts, fmt.Sprintf("%sGroup.%s(%q, %sController.%s)", varName, method, path, varName, handler))
+	}
+
+	edits := []textEdit{statementEdit(fset, src, fn.Body, stmts)}
+	var added []string
+	if imports := missingImports(file, reg.Imports); len(imports) > 0 {
+		edits = append(edits, importEdits(fset, src, file, imports, reg.Names)...)
+		for _, importPath := range imports {
+			added = append(added, importSpec(importPath, reg.Names))
+		}
+	}
+	added = append(added, stmts...)
+
+	out, err := formatGoSource(filename, applyEdits(src, edits), reg.Controller.Data.Module)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, added, nil
+}
+
+// findRouterFunc 查找注册路由的函数及其中的 gin 路由变量名
+// 优先使用接收 *gin.Engine、*gin.RouterGroup 等参数的名称，其次使用函数体内 gin.New()/gin.Default() 赋值的变量
+func findRouterFunc(file *ast.File, funcName string) (*ast.FuncDecl, string) {
+	gin := importName(file, ginImportPath)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || (funcName != "" && fn.Name.Name != funcName) {
+			continue
+		}
+		for _, param := range fn.Type.Params.List {
+			if isGinRouterType(param.Type, gin) && len(param.Names) > 0 {
+				return fn, param.Names[0].Name
+			}
+		}
+		if router := ginEngineVar(fn.Body, gin); router != "" || funcName != "" {
+			return fn, router
+		}
+	}
+	return nil, ""
+}
+
+func isGinRouterType(expr ast.Expr, gin string) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == gin && slices.Contains([]string{"Engine", "RouterGroup", "IRouter", "IRoutes"}, sel.Sel.Name)
+}
+
+func ginEngineVar(body *ast.BlockStmt, gin string) string {
+	for _, stmt := range body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == gin && (sel.Sel.Name == "New" || sel.Sel.Name == "Default") {
+				if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
+					return ident.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// callsConstructor 判断节点中是否存在对 importPath 包中 funcName 函数的调用，按文件中该包的导入名匹配；
+// 文件未导入该包时返回 false，避免与其他包（如不同分组中同名的控制器）混淆
+func callsConstructor(node ast.Node, file *ast.File, importPath, funcName string) bool {
+	pkg := ""
+	for _, spec := range file.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == importPath {
+			pkg = packageName(importPath, path.Base(importPath))
+			if spec.Name != nil {
+				pkg = spec.Name.Name
+			}
+		}
+	}
+	if pkg == "" || pkg == "_" {
+		return false
+	}
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found {
+			return !found
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			found = pkg == "." && fun.Name == funcName
+		case *ast.SelectorExpr:
+			x, ok := fun.X.(*ast.Ident)
+			found = ok && x.Name == pkg && fun.Sel.Name == funcName
+		}
+		return !found
+	})
+	return found
+}
+
+// controllerHandlers 解析控制器源码（src 为 nil 时读取 path），返回接收 *gin.Context 的导出方法名（按声明顺序）
+func controllerHandlers(path string, src any, typeName string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse controller file: %w", err)
+	}
+	var handlers []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || !fn.Name.IsExported() {
+			continue
+		}
+		if receiverName(fn.Recv.List[0].Type) != typeName || fn.Type.Params.NumFields() != 1 {
+			continue
+		}
+		if star, ok := fn.Type.Params.List[0].Type.(*ast.StarExpr); ok {
+			if sel, ok := star.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "Context" {
+				handlers = append(handlers, fn.Name.Name)
+			}
+		}
+	}
+	return handlers, nil
+}
+
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// importName 返回文件中指定导入路径使用的包名，未导入时返回路径最后一段
+func importName(file *ast.File, importPath string) string {
+	for _, spec := range file.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == importPath && spec.Name != nil {
+			return spec.Name.Name
+		}
+	}
+	return importPath[strings.LastIndex(importPath, "/")+1:]
+}
+
+func missingImports(file *ast.File, imports []string) []string {
+	var missing []string
+	for _, importPath := range imports {
+		found := false
+		for _, spec := range file.Imports {
+			if p, _ := strconv.Unquote(spec.Path.Value); p == importPath {
+				found = true
+				break
+			}
+		}
+		if !found && !slices.Contains(missing, importPath) {
+			missing = append(missing, importPath)
+		}
+	}
+	return missing
+}
+
+// importSpec 返回导入声明，names 中有别名时带上别名
+func importSpec(importPath string, names map[string]string) string {
+	if name := names[importPath]; name != "" {
+		return name + " " + strconv.Quote(importPath)
+	}
+	return strconv.Quote(importPath)
+}
+
+// textEdit 是在源码偏移处插入的文本，Delete 为插入前从该偏移起删除的字节数
+type textEdit struct {
+	Offset int
+	Text   string
+	Delete int
+}
+
+// statementEdit 在函数体末尾（若最后一条语句为 return，则在其之前）插入语句
+func statementEdit(fset *token.FileSet, src []byte, body *ast.BlockStmt, stmts []string) textEdit {
+	text := "\n\t" + strings.Join(stmts, "\n\t") + "\n"
+	pos := body.Rbrace
+	if n := len(body.List); n > 0 {
+		if ret, ok := body.List[n-1].(*ast.ReturnStmt); ok {
+			pos = ret.Pos()
+			text += "\n"
+		}
+	}
+	if fset.Position(pos).Line == fset.Position(body.Lbrace).Line {
+		return textEdit{Offset: fset.Position(pos).Offset, Text: text}
+	}
+	if len(body.List) == 0 || (len(body.List) == 1 && pos != body.Rbrace) {
+		text = strings.TrimPrefix(text, "\n")
+	}
+	return textEdit{Offset: lineStart(src, fset.Position(pos).Offset), Text: text}
+}
+
+// importEdits 为文件补充导入，单行导入会被改写为分组形式
+func importEdits(fset *token.FileSet, src []byte, file *ast.File, imports []string, names map[string]string) []textEdit {
+	var specs strings.Builder
+	for _, importPath := range imports {
+		specs.WriteString("\t" + importSpec(importPath, names) + "\n")
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			offset := fset.Position(gen.Rparen).Offset
+			text := specs.String()
+			if lineStart(src, offset) != offset {
+				text = "\n" + text
+			}
+			return []textEdit{{Offset: offset, Text: text}}
+		}
+		return []textEdit{
+			{Offset: fset.Position(gen.Specs[0].Pos()).Offset, Text: "(\n\t"},
+			{Offset: fset.Position(gen.End()).Offset, Text: "\n" + specs.String() + ")"},
+		}
+	}
+	return []textEdit{{Offset: fset.Position(file.Name.End()).Offset, Text: "\n\nimport (\n" + specs.String() + ")"}}
+}
+
+// applyEdits 按偏移从后往前应用插入与删除，避免偏移失效；同一偏移按添加顺序插入
+func applyEdits(src []byte, edits []textEdit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Offset > edits[j].Offset })
+	out := string(src)
+	for i := 0; i < len(edits); {
+		j := i
+		var text strings.Builder
+		deleted := 0
+		for ; j < len(edits) && edits[j].Offset == edits[i].Offset; j++ {
+			text.WriteString(edits[j].Text)
+			deleted = max(deleted, edits[j].Delete)
+		}
+		out = out[:edits[i].Offset] + text.String() + out[edits[i].Offset+deleted:]
+		i = j
+	}
+	return []byte(out)
+}
+
+// lineEdit 返回删除 node 所在整行（含换行符）的编辑
+func lineEdit(fset *token.FileSet, src []byte, node ast.Node) textEdit {
+	start := lineStart(src, fset.Position(node.Pos()).Offset)
+	end := fset.Position(node.End()).Offset
+	if i := strings.IndexByte(string(src[end:]), '\n'); i >= 0 {
+		end += i + 1
+	} else {
+		end = len(src)
+	}
+	return textEdit{Offset: start, Delete: end - start}
+}
+
+func lineStart(src []byte, offset int) int {
+	for offset > 0 && src[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
@@ -0,0 +1,228 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestRegistration(structName string) *routeRegistration {
+	return newRouteRegistration(&generatedFile{
+		TypeName:   "controller",
+		ImportPath: "example.com/app/controller",
+		Data:       &templateData{StructName: structName, Snake: strings.ToLower(structName), Package: "controller", Module: "example.com/app"},
+	})
+}
+
+func TestInsertRoute(t *testing.T) {
+	src := `package router
+
+import "github.com/gin-gonic/gin"
+
+func NewRouter() *gin.Engine {
+	r := gin.New()
+	return r
+}
+`
+	out, added, err := insertRoute("router.go", []byte(src), "", newTestRegistration("Auth"), []string{"List", "Get", "Login"})
+	if err != nil {
+		t.Fatalf("insertRoute failed: %v", err)
+	}
+	got := string(out)
+	for _, want := range []string{
+		"import (\n\t\"github.com/gin-gonic/gin\"\n\n\t\"example.com/app/controller\"\n)",
+		"authController := controller.NewAuthController()",
+		`authGroup := r.Group("/auth")`,
+		`authGroup.GET("", authController.List)`,
+		`authGroup.GET("/:id", authController.Get)`,
+		`authGroup.GET("/login", authController.Login)`,
+		"authController.Login)\n\n\treturn r\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q, got:\n%s", want, got)
+		}
+	}
+	if len(added) != 6 {
+		t.Errorf("added = %d lines; want 6: %v", len(added), added)
+	}
+
+	t.Run("idempotent", func(t *testing.T) {
+		again, added, err := insertRoute("router.go", out, "", newTestRegistration("Auth"), []string{"List"})
+		if err != nil {
+			t.Fatalf("insertRoute failed: %v", err)
+		}
+		if again != nil || added != nil {
+			t.Errorf("expected no changes, got:\n%s", again)
+		}
+	})
+}
+
+func TestInsertRouteRouterGroupParam(t *testing.T) {
+	src := `package router
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func Health(c *gin.Context) {}
+
+func RegisterRoutes(api *gin.RouterGroup) {
+}
+`
+	out, _, err := insertRoute("router.go", []byte(src), "RegisterRoutes", newTestRegistration("User"), []string{"Foo"})
+	if err != nil {
+		t.Fatalf("insertRoute failed: %v", err)
+	}
+	want := `func RegisterRoutes(api *gin.RouterGroup) {
+	userController := controller.NewUserController()
+	userGroup := api.Group("/user")
+	userGroup.GET("/foo", userController.Foo)
+}`
+	if !strings.Contains(string(out), want) {
+		t.Errorf("output does not contain %q, got:\n%s", want, out)
+	}
+}
+
+func TestInsertRouteErrors(t *testing.T) {
+	src := `package router
+
+func Setup() {}
+`
+	if _, _, err := insertRoute("router.go", []byte(src), "", newTestRegistration("User"), nil); err == nil {
+		t.Error("expected error when no router function exists")
+	}
+	if _, _, err := insertRoute("router.go", []byte(src), "Missing", newTestRegistration("User"), nil); err == nil {
+		t.Error("expected error for missing function")
+	}
+	if _, _, err := insertRoute("router.go", []byte(src), "Setup", newTestRegistration("User"), nil); err == nil {
+		t.Error("expected error for function without router")
+	}
+}
+
+func TestRemoveRoute(t *testing.T) {
+	src := `package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"example.com/app/controller"
+)
+
+func NewRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	return r
+}
+`
+	withAuth, _, err := insertRoute("router.go", []byte(src), "", newTestRegistration("Auth"), []string{"List", "Login"})
+	if err != nil {
+		t.Fatalf("insertRoute failed: %v", err)
+	}
+	withBoth, _, err := insertRoute("router.go", withAuth, "", newTestRegistration("User"), []string{"Get"})
+	if err != nil {
+		t.Fatalf("insertRoute failed: %v", err)
+	}
+
+	out, removed, err := removeRoute("router.go", withBoth, "", "User", "example.com/app/controller", "example.com/app")
+	if err != nil {
+		t.Fatalf("removeRoute failed: %v", err)
+	}
+	if string(out) != string(withAuth) {
+		t.Errorf("removing User:\n%s\nwant:\n%s", out, withAuth)
+	}
+	if len(removed) != 3 {
+		t.Errorf("removed = %d lines; want 3: %v", len(removed), removed)
+	}
+
+	out, _, err = removeRoute("router.go", out, "", "Auth", "example.com/app/controller", "example.com/app")
+	if err != nil {
+		t.Fatalf("removeRoute failed: %v", err)
+	}
+	want := strings.Replace(src, "\n\t\"example.com/app/controller\"\n", "", 1)
+	if string(out) != want {
+		t.Errorf("removing Auth:\n%s\nwant:\n%s", out, want)
+	}
+
+	t.Run("not registered", func(t *testing.T) {
+		again, removed, err := removeRoute("router.go", []byte(src), "", "Auth", "example.com/app/controller", "example.com/app")
+		if err != nil || again != nil || removed != nil {
+			t.Errorf("expected no changes, got %v, %v:\n%s", removed, err, again)
+		}
+	})
+
+	t.Run("keeps shared import", func(t *testing.T) {
+		withPets := strings.Replace(string(withAuth), "return r", "r.GET(\"/pets\", controller.ListPets)\n\n\treturn r", 1)
+		out, _, err := removeRoute("router.go", []byte(withPets), "", "Auth", "example.com/app/controller", "example.com/app")
+		if err != nil {
+			t.Fatalf("removeRoute failed: %v", err)
+		}
+		if !strings.Contains(string(out), `"example.com/app/controller"`) {
+			t.Errorf("controller import removed although still used:\n%s", out)
+		}
+	})
+}
+
+func TestRouteSameNameInGroups(t *testing.T) {
+	src := `package router
+
+import "github.com/gin-gonic/gin"
+
+func RegisterRoutes(r *gin.Engine) {
+}
+`
+	// short-nested-names: admin/user 与 user 的控制器同名，只有所在的包不同
+	nested := newRouteRegistration(&generatedFile{
+		TypeName:   "controller",
+		ImportPath: "example.com/app/controller/admin",
+		Data:       &templateData{Name: "admin/user", Group: "admin", StructName: "User", Snake: "user", Package: "admin", Module: "example.com/app"},
+	})
+	withUser, _, err := insertRoute("router.go", []byte(src), "", newTestRegistration("User"), []string{"Get"})
+	if err != nil {
+		t.Fatalf("insertRoute failed: %v", err)
+	}
+	withBoth, added, err := insertRoute("router.go", withUser, "", nested, []string{"Get"})
+	if err != nil {
+		t.Fatalf("insertRoute failed: %v", err)
+	}
+	if added == nil {
+		t.Fatal("nested controller was treated as already registered")
+	}
+	for _, want := range []string{
+		`admincontroller "example.com/app/controller/admin"`,
+		"adminUserController := admincontroller.NewUserController()",
+		`adminUserGroup := r.Group("/admin/user")`,
+	} {
+		if !strings.Contains(string(withBoth), want) {
+			t.Errorf("output does not contain %q, got:\n%s", want, withBoth)
+		}
+	}
+
+	out, _, err := removeRoute("router.go", withBoth, "", "User", "example.com/app/controller/admin", "example.com/app")
+	if err != nil {
+		t.Fatalf("removeRoute failed: %v", err)
+	}
+	if string(out) != string(withUser) {
+		t.Errorf("removing admin/user:\n%s\nwant:\n%s", out, withUser)
+	}
+
+	out, _, err = removeRoute("router.go", withBoth, "", "User", "example.com/app/controller", "example.com/app")
+	if err != nil {
+		t.Fatalf("removeRoute failed: %v", err)
+	}
+	if !strings.Contains(string(out), "adminUserController := admincontroller.NewUserController()") ||
+		strings.Contains(string(out), "userController := controller.NewUserController()") {
+		t.Errorf("removing user should keep admin/user:\n%s", out)
+	}
+
+	t.Run("unique variable names", func(t *testing.T) {
+		// admin_user 与 admin/user 得到相同的变量名前缀
+		flat := newTestRegistration("AdminUser")
+		withFlat, _, err := insertRoute("router.go", withBoth, "", flat, []string{"Get"})
+		if err != nil {
+			t.Fatalf("insertRoute failed: %v", err)
+		}
+		if !strings.Contains(string(withFlat), "adminUser2Controller := controller.NewAdminUserController()") {
+			t.Errorf("expected a numbered variable, got:\n%s", withFlat)
+		}
+	})
+}
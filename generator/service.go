@@ -7,10 +7,10 @@ import (
 )
 
 var serviceCmd = &cobra.Command{
-	Use:                   "service [name]",
+	Use:                   "service [name] [field:type[:option]...]",
 	Short:                 "Generate service",
 	Aliases:               []string{"s"},
-	Args:                  cobra.ExactArgs(1),
+	Args:                  cobra.MinimumNArgs(1),
 	DisableFlagsInUseLine: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return generateFile(cmd, args, "service", defaultServicePath)
@@ -23,4 +23,5 @@ func init() {
 	serviceCmd.Flags().StringP("path", "p", defaultServicePath, "path to service")
 	serviceCmd.Flags().BoolP("force", "f", false, "force overwrite")
 	serviceCmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(serviceCmd)
 }
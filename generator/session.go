@@ -0,0 +1,25 @@
+package generator
+
+import "bufio"
+
+// session 保存一次生成命令执行期间共享的状态，每次执行命令前由 GeneratorCmd 重置
+type session struct {
+	variables map[string]map[string]string // 模板集名称 -> 已解析的模板变量
+	input     *bufio.Reader                // 交互输入，多次询问共用以免丢失缓冲的内容
+	templates map[string]bool              // 已输出来源的模板文件路径
+	paths     map[string]string            // 套件成员的生成目录（相对于项目根目录），优先于 --path 与清单
+	tx        *transaction                 // 进行中的事务，见 inTransaction
+	written   []writtenFile                // 本次命令写入的文件，供钩子使用
+	actions   map[string]int               // 本次命令各操作（actionCreated 等）涉及的文件数，供 apply 汇总
+}
+
+var currentSession = newSession()
+
+func newSession() *session {
+	return &session{
+		variables: make(map[string]map[string]string),
+		templates: make(map[string]bool),
+		paths:     make(map[string]string),
+		actions:   make(map[string]int),
+	}
+}
@@ -0,0 +1,580 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// sqlTable 是从 CREATE TABLE 语句解析出的表结构
+type sqlTable struct {
+	Name    string
+	Columns []sqlColumn
+}
+
+// sqlColumn 是表中的一列
+type sqlColumn struct {
+	Name     string
+	Type     string // 小写的 SQL 类型，不含长度等参数，如 varchar、double precision、int[]
+	Args     string // 类型参数，如 varchar(255) 中的 255
+	Unsigned bool
+	NotNull  bool
+	Primary  bool
+	Unique   bool
+	Index    bool
+}
+
+// sqlToken 是 DDL 中的一个词法单元
+type sqlToken struct {
+	Text   string
+	Quoted bool // 是否为带引号的标识符或字符串
+	Line   int
+}
+
+// tokenizeSQL 将 DDL 拆分为词法单元，忽略 -- 与 /* */ 注释
+func tokenizeSQL(src string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(src)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := line
+			for i += 2; i+1 < len(runes) && (runes[i] != '*' || runes[i+1] != '/'); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+			i += 2
+		case r == '\'' || r == '"' || r == '`':
+			start := line
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("line %d: unterminated quoted text", start)
+				}
+				if runes[i] == r {
+					// 连续两个引号表示转义
+					if i+1 < len(runes) && runes[i+1] == r {
+						sb.WriteRune(r)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, sqlToken{Text: sb.String(), Quoted: true, Line: start})
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{Text: string(runes[start:i]), Line: line})
+		default:
+			tokens = append(tokens, sqlToken{Text: string(r), Line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// parseSQLTables 解析 DDL 中的所有 CREATE TABLE 语句，其他语句被忽略
+func parseSQLTables(src string) ([]sqlTable, error) {
+	tokens, err := tokenizeSQL(src)
+	if err != nil {
+		return nil, err
+	}
+	var tables []sqlTable
+	for _, stmt := range splitSQLStatements(tokens) {
+		if !isCreateTable(stmt) {
+			continue
+		}
+		table, err := parseCreateTable(stmt)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", stmt[0].Line, err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// splitSQLStatements 按分号拆分语句
+func splitSQLStatements(tokens []sqlToken) [][]sqlToken {
+	var stmts [][]sqlToken
+	start := 0
+	for i, tok := range tokens {
+		if tok.Text == ";" && !tok.Quoted {
+			if i > start {
+				stmts = append(stmts, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		stmts = append(stmts, tokens[start:])
+	}
+	return stmts
+}
+
+// isKeyword 判断词法单元是否为指定关键字（不区分大小写）
+func (t sqlToken) isKeyword(words ...string) bool {
+	if t.Quoted {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.Text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCreateTable 判断语句是否为 CREATE [TEMPORARY|UNLOGGED] TABLE
+func isCreateTable(stmt []sqlToken) bool {
+	if len(stmt) < 2 || !stmt[0].isKeyword("create") {
+		return false
+	}
+	i := 1
+	for i < len(stmt) && stmt[i].isKeyword("temporary", "temp", "unlogged", "global", "local") {
+		i++
+	}
+	return i < len(stmt) && stmt[i].isKeyword("table")
+}
+
+// parseCreateTable 解析单条 CREATE TABLE 语句
+func parseCreateTable(stmt []sqlToken) (sqlTable, error) {
+	i := 0
+	for !stmt[i].isKeyword("table") {
+		i++
+	}
+	i++
+	if i+2 < len(stmt) && stmt[i].isKeyword("if") && stmt[i+1].isKeyword("not") && stmt[i+2].isKeyword("exists") {
+		i += 3
+	}
+
+	// 表名可能带有 schema 前缀，只保留最后一段
+	var table sqlTable
+	for i < len(stmt) && stmt[i].Text != "(" {
+		if stmt[i].Text != "." || stmt[i].Quoted {
+			table.Name = stmt[i].Text
+		}
+		i++
+	}
+	if table.Name == "" || i == len(stmt) {
+		return table, fmt.Errorf("invalid CREATE TABLE statement")
+	}
+
+	body, ok := parenthesized(stmt, i)
+	if !ok {
+		return table, fmt.Errorf("table %s: unbalanced parentheses", table.Name)
+	}
+	for _, def := range splitTopLevel(body) {
+		if len(def) == 0 {
+			continue
+		}
+		if isTableConstraint(def) {
+			applyTableConstraint(&table, def)
+			continue
+		}
+		col, err := parseSQLColumn(def)
+		if err != nil {
+			return table, fmt.Errorf("table %s: %w", table.Name, err)
+		}
+		table.Columns = append(table.Columns, col)
+	}
+	if len(table.Columns) == 0 {
+		return table, fmt.Errorf("table %s has no columns", table.Name)
+	}
+	return table, nil
+}
+
+// parenthesized 返回 tokens[open] 处的左括号与其匹配右括号之间的内容
+func parenthesized(tokens []sqlToken, open int) ([]sqlToken, bool) {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].Quoted {
+			continue
+		}
+		switch tokens[i].Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return tokens[open+1 : i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// splitTopLevel 按不在括号内的逗号拆分
+func splitTopLevel(tokens []sqlToken) [][]sqlToken {
+	var parts [][]sqlToken
+	depth, start := 0, 0
+	for i, tok := range tokens {
+		if tok.Quoted {
+			continue
+		}
+		switch tok.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				parts = append(parts, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+func isTableConstraint(def []sqlToken) bool {
+	return def[0].isKeyword("constraint", "primary", "unique", "key", "index", "foreign", "check", "fulltext", "spatial", "exclude")
+}
+
+// applyTableConstraint 将表级约束中的主键、唯一约束与单列索引应用到对应列
+func applyTableConstraint(table *sqlTable, def []sqlToken) {
+	if def[0].isKeyword("constraint") && len(def) > 2 {
+		def = def[2:]
+	}
+	var apply func(*sqlColumn)
+	switch {
+	case def[0].isKeyword("primary"):
+		apply = func(c *sqlColumn) { c.Primary, c.NotNull = true, true }
+	case def[0].isKeyword("unique"):
+		apply = func(c *sqlColumn) { c.Unique = true }
+	case def[0].isKeyword("key", "index"):
+		apply = func(c *sqlColumn) { c.Index = true }
+	default:
+		return
+	}
+
+	open := slices.IndexFunc(def, func(t sqlToken) bool { return t.Text == "(" && !t.Quoted })
+	if open < 0 {
+		return
+	}
+	list, _ := parenthesized(def, open)
+	var names []string
+	for _, part := range splitTopLevel(list) {
+		if len(part) > 0 {
+			names = append(names, part[0].Text)
+		}
+	}
+	// 多列唯一约束与复合索引无法用单个字段的选项表示，只有主键逐列标记
+	if len(names) > 1 && !def[0].isKeyword("primary") {
+		return
+	}
+	for _, name := range names {
+		for i := range table.Columns {
+			if strings.EqualFold(table.Columns[i].Name, name) {
+				apply(&table.Columns[i])
+			}
+		}
+	}
+}
+
+// sqlTypeSuffixes 是可出现在类型名之后、属于类型本身的关键字
+var sqlTypeSuffixes = []string{"precision", "varying"}
+
+// parseSQLColumn 解析列定义：名称、类型与列级约束
+func parseSQLColumn(def []sqlToken) (sqlColumn, error) {
+	if len(def) < 2 {
+		return sqlColumn{}, fmt.Errorf("column %s has no type", def[0].Text)
+	}
+	col := sqlColumn{Name: def[0].Text}
+	words := []string{strings.ToLower(def[1].Text)}
+	i := 2
+	for i < len(def) {
+		tok := def[i]
+		switch {
+		case tok.Text == "(" && !tok.Quoted:
+			args, ok := parenthesized(def, i)
+			if !ok {
+				return col, fmt.Errorf("column %s: unbalanced parentheses", col.Name)
+			}
+			for _, a := range args {
+				col.Args += a.Text
+			}
+			i += len(args) + 2
+		case tok.Text == "[" && i+1 < len(def) && def[i+1].Text == "]":
+			words[len(words)-1] += "[]"
+			i += 2
+		case tok.isKeyword("unsigned"):
+			col.Unsigned = true
+			i++
+		case tok.isKeyword("signed", "zerofill"):
+			i++
+		case tok.isKeyword(sqlTypeSuffixes...):
+			words = append(words, strings.ToLower(tok.Text))
+			i++
+		case tok.isKeyword("with", "without") && i+2 < len(def) && def[i+1].isKeyword("time") && def[i+2].isKeyword("zone"):
+			words = append(words, strings.ToLower(tok.Text), "time", "zone")
+			i += 3
+		default:
+			col.Type = strings.Join(words, " ")
+			parseColumnConstraints(&col, def[i:])
+			return col, nil
+		}
+	}
+	col.Type = strings.Join(words, " ")
+	return col, nil
+}
+
+// parseColumnConstraints 解析列级约束 NOT NULL、PRIMARY KEY、UNIQUE
+func parseColumnConstraints(col *sqlColumn, tokens []sqlToken) {
+	for i := 0; i < len(tokens); i++ {
+		switch {
+		case tokens[i].isKeyword("not") && i+1 < len(tokens) && tokens[i+1].isKeyword("null"):
+			col.NotNull = true
+			i++
+		case tokens[i].isKeyword("primary") && i+1 < len(tokens) && tokens[i+1].isKeyword("key"):
+			col.Primary, col.NotNull = true, true
+			i++
+		case tokens[i].isKeyword("unique"):
+			col.Unique = true
+		case tokens[i].Text == "(" && !tokens[i].Quoted:
+			// 跳过 DEFAULT (...)、CHECK (...) 等括号内容
+			inner, ok := parenthesized(tokens, i)
+			if ok {
+				i += len(inner) + 1
+			}
+		}
+	}
+}
+
+// sqlGoTypes 是 SQL 类型到字段类型的映射，键为小写类型名
+var sqlGoTypes = map[string]string{
+	"bool":    "bool",
+	"boolean": "bool",
+	"bit":     "bool",
+
+	"tinyint":     "int8",
+	"smallint":    "int16",
+	"int2":        "int16",
+	"smallserial": "int16",
+	"mediumint":   "int32",
+	"int":         "int32",
+	"integer":     "int32",
+	"int4":        "int32",
+	"serial":      "int32",
+	"bigint":      "int64",
+	"int8":        "int64",
+	"bigserial":   "int64",
+
+	"real":             "float32",
+	"float4":           "float32",
+	"float":            "float64",
+	"float8":           "float64",
+	"double":           "float64",
+	"double precision": "float64",
+	"decimal":          "decimal",
+	"numeric":          "decimal",
+	"money":            "decimal",
+
+	"char":              "string",
+	"character":         "string",
+	"nchar":             "string",
+	"varchar":           "string",
+	"nvarchar":          "string",
+	"character varying": "string",
+	"text":              "text",
+	"tinytext":          "text",
+	"mediumtext":        "text",
+	"longtext":          "text",
+	"citext":            "text",
+	"enum":              "string",
+	"set":               "string",
+	"inet":              "string",
+	"cidr":              "string",
+	"uuid":              "uuid",
+
+	"date":                        "date",
+	"time":                        "time",
+	"time with time zone":         "time",
+	"time without time zone":      "time",
+	"datetime":                    "datetime",
+	"timestamp":                   "datetime",
+	"timestamptz":                 "datetime",
+	"timestamp with time zone":    "datetime",
+	"timestamp without time zone": "datetime",
+
+	"binary":     "bytes",
+	"varbinary":  "bytes",
+	"blob":       "bytes",
+	"tinyblob":   "bytes",
+	"mediumblob": "bytes",
+	"longblob":   "bytes",
+	"bytea":      "bytes",
+
+	"json":  "json",
+	"jsonb": "json",
+}
+
+// goFieldType 返回列对应的字段类型（可为 fieldTypes 中的简写），无法识别时 ok 为 false
+func (c sqlColumn) goFieldType() (typ string, ok bool) {
+	sqlType := c.Type
+	array := strings.HasSuffix(sqlType, "[]")
+	sqlType = strings.TrimSuffix(sqlType, "[]")
+
+	// MySQL 中 tinyint(1) 与 bit(1) 按惯例表示布尔值
+	if sqlType == "tinyint" && c.Args == "1" {
+		sqlType = "bool"
+	}
+	if sqlType == "bit" && c.Args != "" && c.Args != "1" {
+		sqlType = "varbinary"
+	}
+	typ, ok = sqlGoTypes[sqlType]
+	if !ok {
+		return "", false
+	}
+	if c.Unsigned && strings.HasPrefix(typ, "int") {
+		typ = "u" + typ
+	}
+	if array {
+		if goType, ok := fieldTypes[typ]; ok {
+			typ = goType
+		}
+		typ = "[]" + typ
+	}
+	return typ, true
+}
+
+// fieldSpec 将列转为命令行字段定义 name:type[:option...]，交由 parseFields 解析
+func (c sqlColumn) fieldSpec(typ string) string {
+	spec := c.Name + ":" + typ
+	if c.Primary {
+		spec += ":pk"
+	}
+	if c.Unique && !c.Primary {
+		spec += ":unique"
+	}
+	if c.Index && !c.Primary && !c.Unique {
+		spec += ":index"
+	}
+	// 切片类型本身可以用 nil 表示 NULL，无需再变为指针
+	nilable := strings.HasPrefix(typ, "[]") || typ == "bytes" || typ == "json"
+	if !c.NotNull && !nilable {
+		spec += ":null"
+	}
+	return spec
+}
+
+// singularize 将复数形式的表名转为单数，用作生成的类型名称
+// 只转换以 _ 分隔的最后一个单词，如 order_statuses 转为 order_status
+func singularize(name string) string {
+	i := strings.LastIndex(name, "_") + 1
+	word, lower := name[i:], strings.ToLower(name[i:])
+	singular := singularWord(lower)
+	switch word {
+	case lower:
+	case strings.ToUpper(word):
+		singular = strings.ToUpper(singular)
+	default:
+		// 保留原有的大小写（如 OrderItems），只替换变化的结尾
+		common := 0
+		for common < len(singular) && singular[common] == lower[common] {
+			common++
+		}
+		singular = word[:common] + singular[common:]
+	}
+	return name[:i] + singular
+}
+
+// singularWord 将小写的英文复数单词转为单数：先查不规则与不可数的单词，再按词尾规则转换
+func singularWord(word string) string {
+	if singular, ok := irregularPlurals[word]; ok {
+		return singular
+	}
+	if uncountableNouns[word] {
+		return word
+	}
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zzes"), strings.HasSuffix(word, "uses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s") && len(word) > 1:
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// irregularPlurals 是不符合词尾规则的复数单词及其单数形式，
+// 包括单数以 -ie、-se、-che 结尾等会被词尾规则误转的单词
+var irregularPlurals = map[string]string{
+	"people":     "person",
+	"children":   "child",
+	"men":        "man",
+	"women":      "woman",
+	"mice":       "mouse",
+	"geese":      "goose",
+	"feet":       "foot",
+	"teeth":      "tooth",
+	"indices":    "index",
+	"matrices":   "matrix",
+	"vertices":   "vertex",
+	"analyses":   "analysis",
+	"crises":     "crisis",
+	"theses":     "thesis",
+	"heroes":     "hero",
+	"potatoes":   "potato",
+	"tomatoes":   "tomato",
+	"echoes":     "echo",
+	"leaves":     "leaf",
+	"halves":     "half",
+	"shelves":    "shelf",
+	"knives":     "knife",
+	"wives":      "wife",
+	"lives":      "life",
+	"movies":     "movie",
+	"cookies":    "cookie",
+	"pies":       "pie",
+	"ties":       "tie",
+	"lies":       "lie",
+	"zombies":    "zombie",
+	"rookies":    "rookie",
+	"calories":   "calorie",
+	"selfies":    "selfie",
+	"caches":     "cache",
+	"niches":     "niche",
+	"headaches":  "headache",
+	"uses":       "use",
+	"causes":     "cause",
+	"houses":     "house",
+	"warehouses": "warehouse",
+	"excuses":    "excuse",
+	"abuses":     "abuse",
+	"fuses":      "fuse",
+	"axes":       "axe",
+}
+
+// uncountableNouns 是单复数同形的单词，保持不变
+var uncountableNouns = setOf("series", "species", "news", "data", "metadata", "information", "equipment",
+	"feedback", "sheep", "fish", "deer", "media")
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSQLTables(t *testing.T) {
+	src := `-- accounts
+CREATE TABLE IF NOT EXISTS public.accounts (
+	id BIGSERIAL PRIMARY KEY,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	nickname character varying(100) DEFAULT 'a;b',
+	age INT UNSIGNED NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+	tags text[] /* labels */
+);
+CREATE INDEX idx_email ON accounts (email);
+CREATE TABLE ` + "`order_items`" + ` (
+	` + "`order_id`" + ` bigint NOT NULL,
+	sku varchar(64) NOT NULL,
+	price decimal(10,2),
+	PRIMARY KEY (order_id, sku),
+	KEY idx_price (price)
+) ENGINE=InnoDB;`
+
+	tables, err := parseSQLTables(src)
+	if err != nil {
+		t.Fatalf("parseSQLTables failed: %v", err)
+	}
+	want := []sqlTable{
+		{Name: "accounts", Columns: []sqlColumn{
+			{Name: "id", Type: "bigserial", NotNull: true, Primary: true},
+			{Name: "email", Type: "varchar", Args: "255", NotNull: true, Unique: true},
+			{Name: "nickname", Type: "character varying", Args: "100"},
+			{Name: "age", Type: "int", Unsigned: true, NotNull: true},
+			{Name: "created_at", Type: "timestamp with time zone", NotNull: true},
+			{Name: "tags", Type: "text[]"},
+		}},
+		{Name: "order_items", Columns: []sqlColumn{
+			{Name: "order_id", Type: "bigint", NotNull: true, Primary: true},
+			{Name: "sku", Type: "varchar", Args: "64", NotNull: true, Primary: true},
+			{Name: "price", Type: "decimal", Args: "10,2", Index: true},
+		}},
+	}
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("parseSQLTables =\n%+v\nwant\n%+v", tables, want)
+	}
+
+	if _, err := parseSQLTables("CREATE TABLE broken (id int"); err == nil {
+		t.Error("expected error for unbalanced parentheses")
+	}
+}
+
+func TestSQLColumnFieldSpec(t *testing.T) {
+	tests := []struct {
+		col  sqlColumn
+		want string
+	}{
+		{sqlColumn{Name: "id", Type: "bigint", NotNull: true, Primary: true}, "id:int64:pk"},
+		{sqlColumn{Name: "email", Type: "varchar", NotNull: true, Unique: true}, "email:string:unique"},
+		{sqlColumn{Name: "age", Type: "int", Unsigned: true}, "age:uint32:null"},
+		{sqlColumn{Name: "active", Type: "tinyint", Args: "1", NotNull: true}, "active:bool"},
+		{sqlColumn{Name: "price", Type: "numeric", Index: true, NotNull: true}, "price:decimal:index"},
+		{sqlColumn{Name: "born", Type: "date"}, "born:date:null"},
+		{sqlColumn{Name: "tags", Type: "text[]"}, "tags:[]string"},
+		{sqlColumn{Name: "data", Type: "jsonb"}, "data:json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			typ, ok := tt.col.goFieldType()
+			if !ok {
+				t.Fatalf("goFieldType(%q) not supported", tt.col.Type)
+			}
+			if got := tt.col.fieldSpec(typ); got != tt.want {
+				t.Errorf("fieldSpec = %q; want %q", got, tt.want)
+			}
+			if _, err := parseField(tt.want); err != nil {
+				t.Errorf("parseField(%q) failed: %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, ok := (sqlColumn{Type: "geometry"}).goFieldType(); ok {
+		t.Error("expected geometry to be unsupported")
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	tests := map[string]string{
+		"users":           "user",
+		"categories":      "category",
+		"addresses":       "address",
+		"boxes":           "box",
+		"status":          "status",
+		"person":          "person",
+		"statuses":        "status",
+		"movies":          "movie",
+		"cases":           "case",
+		"buses":           "bus",
+		"taxes":           "tax",
+		"caches":          "cache",
+		"houses":          "house",
+		"warehouses":      "warehouse",
+		"responses":       "response",
+		"people":          "person",
+		"series":          "series",
+		"analyses":        "analysis",
+		"order_statuses":  "order_status",
+		"user_movies":     "user_movie",
+		"Statuses":        "Status",
+		"ORDER_ITEMS":     "ORDER_ITEM",
+		"OrderCategories": "OrderCategory",
+	}
+	for in, want := range tests {
+		if got := singularize(in); got != want {
+			t.Errorf("singularize(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
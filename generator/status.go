@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Report generated files that drifted from or lag behind their templates",
+	Long: `Compare the generated files recorded in .gouno.lock with the project and the
+current templates:
+
+  modified   the file was edited after it was generated
+  outdated   the template it was rendered from has changed since (e.g. a new
+             template set version); gouno gen upgrade re-renders it
+  missing    the file was deleted
+  orphaned   the template it was rendered from no longer exists
+
+Only files that are not up to date are listed unless --all is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		statuses, err := generatedFileStatuses()
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		if len(statuses) == 0 {
+			fmt.Fprintf(out, "No generated files recorded in %s\n", lockFileName)
+			return nil
+		}
+
+		all, _ := cmd.Flags().GetBool("all")
+		counts := make(map[string]int)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tFILE\tTEMPLATE")
+		listed := 0
+		for _, status := range statuses {
+			for _, state := range status.states() {
+				counts[state]++
+			}
+			if status.upToDate() && !all {
+				continue
+			}
+			template := status.Entry.templateLabel()
+			if status.CurrentVersion != status.Entry.TemplateSetVersion && status.CurrentVersion != "" {
+				template += " (now " + status.CurrentVersion + ")"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", strings.Join(status.states(), ", "), status.Key, template)
+			listed++
+		}
+		if listed > 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintln(out)
+		}
+
+		var summary []string
+		for _, state := range []string{stateModified, stateOutdated, stateMissing, stateOrphaned, stateUpToDate} {
+			if counts[state] > 0 {
+				summary = append(summary, fmt.Sprintf("%d %s", counts[state], state))
+			}
+		}
+		fmt.Fprintf(out, "%d generated file(s): %s\n", len(statuses), strings.Join(summary, ", "))
+		return nil
+	},
+}
+
+// 生成文件相对于锁记录的状态
+const (
+	stateUpToDate = "up to date"
+	stateModified = "modified"
+	stateOutdated = "outdated"
+	stateMissing  = "missing"
+	stateOrphaned = "orphaned"
+)
+
+// generatedFileStatus 是锁中记录的一个生成文件的当前状态
+type generatedFileStatus struct {
+	Key   string // 锁中的键，相对于项目根目录
+	Path  string // 绝对路径
+	Entry *lockEntry
+
+	Modified bool // 文件内容与生成时不同
+	Outdated bool // 模板内容与生成时不同
+	Missing  bool // 文件已被删除
+	Orphaned bool // 模板已不存在
+
+	CurrentVersion string // 模板集当前的版本
+	Template       string // 模板当前的内容，Orphaned 时为空
+}
+
+// states 返回状态的描述，可能同时处于 modified 与 outdated
+func (s *generatedFileStatus) states() []string {
+	var states []string
+	switch {
+	case s.Missing:
+		states = append(states, stateMissing)
+	case s.Modified:
+		states = append(states, stateModified)
+	}
+	switch {
+	case s.Orphaned:
+		states = append(states, stateOrphaned)
+	case s.Outdated:
+		states = append(states, stateOutdated)
+	}
+	if len(states) == 0 {
+		states = append(states, stateUpToDate)
+	}
+	return states
+}
+
+func (s *generatedFileStatus) upToDate() bool {
+	return !s.Modified && !s.Outdated && !s.Missing && !s.Orphaned
+}
+
+// generatedFileStatuses 按路径顺序返回锁中记录的每个生成文件的状态
+func generatedFileStatuses() ([]*generatedFileStatus, error) {
+	lock, err := loadLock()
+	if err != nil {
+		return nil, err
+	}
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	type templateKey struct{ set, name string }
+	templates := make(map[templateKey][]templateFile)
+	var statuses []*generatedFileStatus
+	for _, key := range slices.Sorted(maps.Keys(lock.Files)) {
+		entry := lock.Files[key]
+		status := &generatedFileStatus{Key: key, Path: filepath.Join(projectRoot, filepath.FromSlash(key)), Entry: entry}
+
+		if content, err := os.ReadFile(status.Path); os.IsNotExist(err) {
+			status.Missing = true
+		} else if err != nil {
+			return nil, err
+		} else {
+			status.Modified = contentHash(content) != entry.OutputHash
+		}
+
+		manifest, err := loadManifest(entry.TemplateSet)
+		if err != nil {
+			return nil, err
+		}
+		status.CurrentVersion = templateSetVersion(entry.TemplateSet, manifest)
+		tk := templateKey{entry.TemplateSet, entry.Template}
+		files, ok := templates[tk]
+		if !ok {
+			files, _, _ = lookupTemplate(entry.TemplateSet, manifest, entry.Template)
+			templates[tk] = files
+		}
+		i := slices.IndexFunc(files, func(f templateFile) bool { return f.Name == entry.TemplateFile })
+		if i < 0 {
+			status.Orphaned = true
+		} else {
+			status.Template = files[i].Content
+			status.Outdated = contentHash([]byte(status.Template)) != entry.TemplateHash
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func init() {
+	statusCmd.Flags().Bool("all", false, "also list files that are up to date")
+}
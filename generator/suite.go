@@ -1,24 +1,150 @@
 package generator
 
-import "github.com/spf13/cobra"
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
 
 var suiteCmd = &cobra.Command{
-	Use:                   "suite [name]",
-	Short:                 "Generate suite (domain, repository, service)",
+	Use:   "suite [name] [field:type[:option]...]",
+	Short: "Generate suite (domain, repository, service)",
+	Long: `Generate every type of a suite for one resource.
+
+Without --kind the default suite (domain, repository, service) is generated.
+Named suites are declared in .gouno.yaml, with optional per-member paths:
+
+  suites:
+    api:
+      - domain
+      - repository
+      - service
+      - type: controller
+        path: internal/http/controller
+      - task
+
+--path moves the whole suite under another base directory. If any member
+fails, the files written so far are rolled back.`,
 	DisableFlagsInUseLine: true,
-	Args:                  cobra.ExactArgs(1),
+	Args:                  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := generateFile(cmd, args, "domain", defaultDomainPath); err != nil {
-			return err
+		return generateSuite(cmd, args)
+	},
+}
+
+// defaultSuite 是未指定 --kind 时生成的套件，可在 .gouno.yaml 中以同名套件覆盖
+const defaultSuite = "default"
+
+// builtinTypePaths 是内置生成类型的默认目录
+var builtinTypePaths = map[string]string{
+	"domain":     defaultDomainPath,
+	"repository": defaultRepositoryPath,
+	"service":    defaultServicePath,
+	"controller": defaultControllerPath,
+	"task":       defaultTaskPath,
+}
+
+// generateSuite 在事务中依次生成套件中的各个类型，任一类型失败时回滚已写入的文件
+func generateSuite(cmd *cobra.Command, args []string) error {
+	kind, members, err := resolveSuite(cmd)
+	if err != nil {
+		return err
+	}
+	defer clear(currentSession.paths)
+
+	return inTransaction(cmd, func() error {
+		for _, member := range members {
+			file, err := generateTemplateFile(cmd, args, member.Type, member.Type, currentSession.paths[member.Type])
+			if err != nil {
+				return fmt.Errorf("suite %q: %s: %w", kind, member.Type, err)
+			}
+			if member.Type == "controller" {
+				if err := registerRoute(cmd, newRouteRegistration(file)); err != nil {
+					return fmt.Errorf("suite %q: %s: %w", kind, member.Type, err)
+				}
+			}
 		}
-		if err := generateFile(cmd, args, "repository", defaultRepositoryPath); err != nil {
-			return err
+		return nil
+	})
+}
+
+// resolveSuite 返回 --kind 指定的套件及其成员，并在 currentSession.paths 中设置各成员的生成目录，
+// 使各成员模板中的 .Packages 指向套件实际生成的位置；调用方负责在结束后清空
+func resolveSuite(cmd *cobra.Command) (string, []SuiteMember, error) {
+	kind := defaultSuite
+	if flag := cmd.Flag("kind"); flag != nil {
+		kind = cmp.Or(flag.Value.String(), defaultSuite)
+	}
+	members, err := suiteMembers(kind)
+	if err != nil {
+		return "", nil, err
+	}
+	manifest, err := loadManifest(resolveTemplateSet(cmd))
+	if err != nil {
+		return "", nil, err
+	}
+
+	base := ""
+	if flag := cmd.Flag("path"); flag != nil && flag.Changed {
+		base = flag.Value.String()
+	}
+	for _, member := range members {
+		defaultPath, ok := builtinTypePaths[member.Type]
+		if _, isKind := manifest.kind(member.Type); isKind {
+			defaultPath, ok = defaultKindPath(member.Type), true
 		}
-		return generateFile(cmd, args, "service", defaultServicePath)
-	},
+		if !ok {
+			clear(currentSession.paths)
+			return "", nil, fmt.Errorf("suite %q: unknown type %q (available: %s)", kind, member.Type, strings.Join(suiteTypes(manifest), ", "))
+		}
+		currentSession.paths[member.Type] = filepath.Join(base, cmp.Or(member.Path, manifest.path(member.Type), defaultPath))
+	}
+	return kind, members, nil
+}
+
+// suiteMembers 返回套件的成员：.gouno.yaml 中的同名套件，或内置的 default 套件
+func suiteMembers(kind string) ([]SuiteMember, error) {
+	cfg := loadProjectConfig()
+	if cfg != nil {
+		if members, ok := cfg.Suites[kind]; ok {
+			if len(members) == 0 {
+				return nil, fmt.Errorf("suite %q has no members", kind)
+			}
+			return members, nil
+		}
+	}
+	if kind == defaultSuite {
+		return []SuiteMember{{Type: "domain"}, {Type: "repository"}, {Type: "service"}}, nil
+	}
+
+	available := []string{defaultSuite}
+	if cfg != nil {
+		available = append(available, slices.Collect(maps.Keys(cfg.Suites))...)
+	}
+	slices.Sort(available)
+	return nil, fmt.Errorf("unknown suite %q (available: %s)", kind, strings.Join(slices.Compact(available), ", "))
+}
+
+// suiteTypes 返回可用作套件成员的类型：内置类型与模板集声明的自定义类型
+func suiteTypes(manifest *TemplateManifest) []string {
+	types := slices.Collect(maps.Keys(builtinTypePaths))
+	if manifest != nil {
+		types = append(types, slices.Collect(maps.Keys(manifest.Kinds))...)
+	}
+	slices.Sort(types)
+	return types
 }
 
 func init() {
+	suiteCmd.Flags().StringP("kind", "k", "", "suite to generate, as declared under suites in .gouno.yaml (default \"default\")")
+	suiteCmd.Flags().StringP("path", "p", "", "base directory for the suite's member paths")
 	suiteCmd.Flags().BoolP("force", "f", false, "force overwrite")
 	suiteCmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(suiteCmd)
+	suiteCmd.Flags().Bool("no-route", false, "skip route registration in the configured router file")
 }
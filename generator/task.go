@@ -23,4 +23,5 @@ func init() {
 	taskCmd.Flags().StringP("path", "p", defaultTaskPath, "path to task")
 	taskCmd.Flags().BoolP("force", "f", false, "force overwrite")
 	taskCmd.Flags().String("template-set", "", "template set name")
+	addGenerateFlags(taskCmd)
 }
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// templateExt 是模板文件的扩展名
+const templateExt = ".tmpl"
+
+var templateCmd = &cobra.Command{
+	Use:     "template",
+	Short:   "Manage template sets",
+	Aliases: []string{"tpl"},
+}
+
+func init() {
+	templateCmd.AddCommand(
+		templateListCmd,
+		templateShowCmd,
+		templateInstallCmd,
+		templateRemoveCmd,
+		templateEjectCmd,
+	)
+}
+
+// checkTemplateSetName 检查模板集名称能否作为 ~/.gouno/templates 下的目录名
+func checkTemplateSetName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid template set name %q", name)
+	}
+	return nil
+}
+
+// installedTemplateSet 返回 ~/.gouno/templates 中模板集的目录，未安装时返回 false
+func installedTemplateSet(name string) (string, bool, error) {
+	if err := checkTemplateSetName(name); err != nil {
+		return "", false, err
+	}
+	root, err := templateSetDir()
+	if err != nil {
+		return "", false, err
+	}
+	dir := filepath.Join(root, name)
+	info, err := os.Stat(dir)
+	return dir, err == nil && info.IsDir(), nil
+}
+
+// templateSetLocation 是模板集所在的目录及其在查找链中的层
+type templateSetLocation struct {
+	Name  string
+	Dir   string
+	Layer string
+}
+
+// availableTemplateSets 返回查找链各层中的模板集，同名时只保留优先级最高的一个，按名称排序
+func availableTemplateSets() ([]templateSetLocation, error) {
+	seen := make(map[string]bool)
+	var sets []templateSetLocation
+	for _, layer := range templateLayers() {
+		entries, err := os.ReadDir(layer.Dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() || checkTemplateSetName(name) != nil || seen[name] {
+				continue
+			}
+			seen[name] = true
+			sets = append(sets, templateSetLocation{name, filepath.Join(layer.Dir, name), layer.Name})
+		}
+	}
+	slices.SortFunc(sets, func(a, b templateSetLocation) int { return strings.Compare(a.Name, b.Name) })
+	return sets, nil
+}
+
+// findTemplateSet 返回查找链中优先级最高的同名模板集
+func findTemplateSet(name string) (templateSetLocation, bool, error) {
+	if err := checkTemplateSetName(name); err != nil {
+		return templateSetLocation{}, false, err
+	}
+	for _, layer := range templateLayers() {
+		dir := filepath.Join(layer.Dir, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return templateSetLocation{name, dir, layer.Name}, true, nil
+		}
+	}
+	return templateSetLocation{}, false, nil
+}
+
+// templateSetTypes 返回模板集目录中提供的模板类型（*.tmpl 文件名与目录模板名），按名称排序
+func templateSetTypes(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var types []string
+	for _, entry := range entries {
+		switch name, isTemplate := strings.CutSuffix(entry.Name(), templateExt); {
+		case isTemplate && entry.Type().IsRegular():
+			types = append(types, name)
+		case entry.IsDir() && !strings.HasPrefix(name, "."):
+			types = append(types, name+"/")
+		}
+	}
+	slices.Sort(types)
+	return types, nil
+}
+
+// builtinTemplateTypes 返回内置模板的类型，按名称排序
+func builtinTemplateTypes() []string {
+	return slices.Sorted(maps.Keys(builtinTemplates))
+}
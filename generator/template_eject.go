@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var templateEjectCmd = &cobra.Command{
+	Use:   "eject [type...]",
+	Short: "Copy builtin templates into a template set for customization",
+	Long: `Copy builtin templates into ~/.gouno/templates/<set> so they can be edited.
+
+Without arguments every builtin template is ejected. The set defaults to the
+project's template set (--template-set > .gouno.yaml > "default"); templates
+ejected into "default" replace the builtin ones for every project.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		types := args
+		if len(types) == 0 {
+			types = builtinTemplateTypes()
+		}
+		for _, typeName := range types {
+			if _, ok := builtinTemplates[typeName]; !ok {
+				return fmt.Errorf("unknown template type %q (available: %s)", typeName, strings.Join(builtinTemplateTypes(), ", "))
+			}
+		}
+
+		name := resolveTemplateSet(cmd)
+		dir, _, err := installedTemplateSet(name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create template set %s: %w", name, err)
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		for _, typeName := range types {
+			path := filepath.Join(dir, typeName+templateExt)
+			if _, err := os.Stat(path); err == nil && !force {
+				cmd.Printf("Skipped %s template (already exists): %s (use --force to overwrite)\n", typeName, path)
+				continue
+			}
+			content := builtinTemplates[typeName]
+			if !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return fmt.Errorf("failed to write %s template: %w", typeName, err)
+			}
+			cmd.Printf("Ejected %s template: %s\n", typeName, path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	templateEjectCmd.Flags().BoolP("force", "f", false, "overwrite templates that already exist in the set")
+	templateEjectCmd.Flags().String("template-set", "", "template set to eject into")
+}
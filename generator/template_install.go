@@ -0,0 +1,259 @@
+package generator
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"cmp"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var templateInstallCmd = &cobra.Command{
+	Use:   "install [dir|archive] [name]",
+	Short: "Install a template set from a local directory or a .tar.gz/.zip archive",
+	Long: `Install a template set into ~/.gouno/templates/<name>.
+
+The name defaults to the manifest name, or to the source's base name when the
+set has no template.yaml. Archives containing a single top-level directory are
+unpacked from that directory.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source := args[0]
+		root, err := templateSetDir()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(root, 0755); err != nil {
+			return fmt.Errorf("failed to create template directory: %w", err)
+		}
+
+		// 先解压或复制到临时目录，校验通过后再移动到目标位置
+		staging, err := os.MkdirTemp(root, ".install-")
+		if err != nil {
+			return fmt.Errorf("failed to create staging directory: %w", err)
+		}
+		defer os.RemoveAll(staging)
+		if err := unpackTemplateSet(source, staging); err != nil {
+			return fmt.Errorf("failed to install template set from %s: %w", source, err)
+		}
+		dir, err := templateSetRoot(staging)
+		if err != nil {
+			return err
+		}
+
+		manifest, err := readManifest(dir, source)
+		if err != nil {
+			return err
+		}
+		name := archiveBaseName(source)
+		if manifest != nil {
+			name = cmp.Or(manifest.Name, name)
+		}
+		if len(args) == 2 {
+			name = args[1]
+		}
+		if err := checkTemplateSetName(name); err != nil {
+			return err
+		}
+		types, err := templateSetTypes(dir)
+		if err != nil {
+			return err
+		}
+		if len(types) == 0 {
+			return fmt.Errorf("no templates (*%s) found in %s", templateExt, source)
+		}
+
+		target := filepath.Join(root, name)
+		if _, err := os.Stat(target); err == nil {
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				return fmt.Errorf("template set %q is already installed (use --force to replace it)", name)
+			}
+			if err := os.RemoveAll(target); err != nil {
+				return fmt.Errorf("failed to remove template set %s: %w", name, err)
+			}
+		}
+		if err := os.Rename(dir, target); err != nil {
+			return fmt.Errorf("failed to install template set %s: %w", name, err)
+		}
+		cmd.Printf("Installed template set %s (%s) to %s\n", name, strings.Join(types, ", "), target)
+		if manifest != nil && len(manifest.Hooks) > 0 {
+			cmd.Printf("The template set declares hooks, which only run once listed in trusted-hooks in %s or with --trust-hooks:\n", configFileName)
+			for _, hook := range manifest.Hooks {
+				cmd.Printf("  %s\n", hook)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	templateInstallCmd.Flags().BoolP("force", "f", false, "replace an installed template set with the same name")
+}
+
+// unpackTemplateSet 将目录、.tar.gz/.tgz 或 .zip 形式的模板集复制或解压到 dst
+func unpackTemplateSet(source, dst string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	switch {
+	case info.IsDir():
+		return os.CopyFS(dst, os.DirFS(source))
+	case strings.HasSuffix(source, ".tar.gz") || strings.HasSuffix(source, ".tgz"):
+		return extractTarGz(source, dst)
+	case strings.HasSuffix(source, ".zip"):
+		return extractZip(source, dst)
+	}
+	return fmt.Errorf("unsupported source, expected a directory, .tar.gz, .tgz or .zip")
+}
+
+// templateSetRoot 返回解压结果中模板集所在的目录：
+// 顶层只有一个包含清单或模板的目录时（常见的打包方式）返回该目录
+func templateSetRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return dir, nil
+	}
+	sub := filepath.Join(dir, entries[0].Name())
+	types, err := templateSetTypes(sub)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(sub, manifestFileName)); err == nil || len(types) > 0 {
+		return sub, nil
+	}
+	return dir, nil
+}
+
+// archiveBaseName 返回去掉归档扩展名后的源文件名
+func archiveBaseName(source string) string {
+	base := filepath.Base(filepath.Clean(source))
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if name, ok := strings.CutSuffix(base, ext); ok {
+			return name
+		}
+	}
+	return base
+}
+
+// archiveEntryPath 返回归档条目在 dst 中的路径，拒绝绝对路径和跳出 dst 的条目
+func archiveEntryPath(dst, name string) (string, error) {
+	name = filepath.Clean(filepath.FromSlash(name))
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("archive entry %q escapes the template directory", name)
+	}
+	return filepath.Join(dst, name), nil
+}
+
+// writeArchiveFile 将归档中的普通文件写入 path
+func writeArchiveFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// extractTarGz 解压 .tar.gz 归档，只保留目录与普通文件
+func extractTarGz(source, dst string) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeDir {
+			continue
+		}
+		// macOS 打包时附带的元数据文件
+		if strings.HasPrefix(filepath.Base(header.Name), "._") {
+			continue
+		}
+		path, err := archiveEntryPath(dst, header.Name)
+		if err != nil {
+			return err
+		}
+		if header.Typeflag == tar.TypeDir {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeArchiveFile(path, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// extractZip 解压 .zip 归档，只保留目录与普通文件
+func extractZip(source, dst string) error {
+	zr, err := zip.OpenReader(source)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, file := range zr.File {
+		mode := file.Mode()
+		if !mode.IsDir() && !mode.IsRegular() {
+			continue
+		}
+		if strings.HasPrefix(file.Name, "__MACOSX/") {
+			continue
+		}
+		path, err := archiveEntryPath(dst, file.Name)
+		if err != nil {
+			return err
+		}
+		if mode.IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := extractZipFile(file, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractZipFile 解压 zip 归档中的单个文件
+func extractZipFile(file *zip.File, path string) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return writeArchiveFile(path, r)
+}
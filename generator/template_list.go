@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/rushairer/gouno"
+	"github.com/spf13/cobra"
+)
+
+var templateListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List template sets from the project, template-paths, home directory and builtins",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sets, err := availableTemplateSets()
+		if err != nil {
+			return fmt.Errorf("failed to list template sets: %w", err)
+		}
+		current := resolveTemplateSet(cmd)
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "\tNAME\tVERSION\tTYPES\tLOCATION\tDESCRIPTION")
+		row := func(name, version string, types int, location, description string) {
+			marker := ""
+			if name == current {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", marker, name, version, types, location, description)
+		}
+
+		// 查找链中的 default 模板集会覆盖内置模板，此时只列出覆盖后的版本
+		if !slices.ContainsFunc(sets, func(set templateSetLocation) bool { return set.Name == "default" }) {
+			row("default", gouno.Version, len(builtinTemplates), layerBuiltin, "builtin templates")
+		}
+		for _, set := range sets {
+			manifest, err := readManifest(set.Dir, set.Name)
+			if err != nil {
+				row(set.Name, "?", 0, set.Layer, err.Error())
+				continue
+			}
+			types, _ := templateSetTypes(set.Dir)
+			if manifest == nil {
+				manifest = &TemplateManifest{}
+			}
+			row(set.Name, cmp.Or(manifest.Version, "-"), len(types), set.Layer, manifest.Description)
+		}
+		return w.Flush()
+	},
+}
@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var templateRemoveCmd = &cobra.Command{
+	Use:     "remove [set]",
+	Short:   "Remove an installed template set",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		dir, installed, err := installedTemplateSet(name)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			if name == "default" {
+				return fmt.Errorf("template set %q is builtin and cannot be removed", name)
+			}
+			return fmt.Errorf("template set %q is not installed", name)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove template set %s: %w", name, err)
+		}
+		cmd.Printf("Removed template set %s: %s\n", name, dir)
+		return nil
+	},
+}
@@ -2,8 +2,10 @@ package generator
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -11,7 +13,52 @@ import (
 
 // GounoConfig 项目级 .gouno.yaml 配置
 type GounoConfig struct {
-	TemplateSet string `yaml:"template-set"`
+	TemplateSet string       `yaml:"template-set"`
+	WithTest    bool         `yaml:"with-test"`
+	Router      RouterConfig `yaml:"router"`
+
+	// TemplatePaths 是额外的模板集根目录，优先级低于项目的 .gouno/templates、高于 ~/.gouno/templates
+	TemplatePaths []string `yaml:"template-paths"`
+
+	// Variables 为模板集变量提供项目级取值，见 TemplateVariable
+	Variables map[string]string `yaml:"variables"`
+
+	// Suites 是具名的生成套件，gouno gen suite --kind <name> 按顺序生成其中的类型
+	//
+	//	suites:
+	//	  api:
+	//	    - domain
+	//	    - repository
+	//	    - service
+	//	    - type: controller
+	//	      path: internal/http/controller
+	Suites map[string][]SuiteMember `yaml:"suites"`
+
+	// ShortNestedNames 使嵌套名称（如 admin/user）的结构体名只使用最后一段（UserController），
+	// 默认包含分组（AdminUserController）
+	ShortNestedNames bool `yaml:"short-nested-names"`
+
+	// Hooks 在生成命令写入文件后执行，位于模板集清单中声明的钩子之后，见 Hook
+	Hooks []Hook `yaml:"hooks"`
+
+	// TrustedHooks 是允许执行的模板集清单钩子命令，须与清单中的 run 完全相同；
+	// 未列出的清单钩子会被跳过，除非指定 --trust-hooks
+	TrustedHooks []string `yaml:"trusted-hooks"`
+}
+
+// SuiteMember 是套件中的一个类型，不覆盖目录时可简写为类型名称
+type SuiteMember struct {
+	Type string `yaml:"type"`
+	Path string `yaml:"path"` // 该类型的生成目录，相对于项目根目录（或 --path）
+}
+
+// UnmarshalYAML 支持 "domain" 与 {type: domain, path: ...} 两种写法
+func (m *SuiteMember) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&m.Type)
+	}
+	type plain SuiteMember
+	return node.Decode((*plain)(m))
 }
 
 const configFileName = ".gouno.yaml"
@@ -25,54 +72,113 @@ var builtinTemplates = map[string]string{
 	"service":    serviceTemplate,
 	"controller": controllerTemplate,
 	"task":       taskTemplate,
+	"mock":       mockTemplate,
+
+	"crud_repository": crudRepositoryTemplate,
+	"crud_service":    crudServiceTemplate,
+	"crud_controller": crudControllerTemplate,
+
+	"openapi_dto":        openapiDTOTemplate,
+	"openapi_controller": openapiControllerTemplate,
+
+	"domain_test":          domainTestTemplate,
+	"repository_test":      repositoryTestTemplate,
+	"service_test":         serviceTestTemplate,
+	"controller_test":      controllerTestTemplate,
+	"task_test":            taskTestTemplate,
+	"crud_repository_test": crudRepositoryTestTemplate,
+	"crud_service_test":    crudServiceTestTemplate,
+	"crud_controller_test": crudControllerTestTemplate,
 }
 
-const domainTemplate = `package domain
+const domainTemplate = `package {{.Package}}
 
-import "context"
+import (
+	"context"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
 
-type %s struct {
+type {{.StructName}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`{{.Tag}}`" + `
+{{- end}}
 }
 
-func New%s() *%s {
-	return &%s{}
+func New{{.StructName}}({{params .Fields}}) *{{.StructName}} {
+	return &{{.StructName}}{ {{- range .Fields}}
+		{{.Name}}: {{.Param}},
+{{- end}}
+{{- if .Fields}}
+	{{end}}}
 }
 
-func (d *%s) Foo(ctx context.Context) (bar string, err error) {
+func (d *{{.StructName}}) Foo(ctx context.Context) (bar string, err error) {
 	return
 }`
 
-const repositoryTemplate = `package repository
+const repositoryTemplate = `package {{.Package}}
 
-import "context"
+import (
+	"context"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
 
-type %sRepository struct {
+type {{.StructName}}Repository struct {
 }
 
-func New%sRepository() *%sRepository {
-	return &%sRepository{}
+func New{{.StructName}}Repository() *{{.StructName}}Repository {
+	return &{{.StructName}}Repository{}
 }
 
-func (r *%sRepository) Foo(ctx context.Context) (bar string, err error) {
+func (r *{{.StructName}}Repository) Foo(ctx context.Context) (bar string, err error) {
 	return
-}`
+}
+{{- if .Fields}}
 
-const serviceTemplate = `package service
+func (r *{{.StructName}}Repository) Create(ctx context.Context, {{params .Fields}}) error {
+	return nil
+}
+{{- end}}
+{{- range .Fields}}
+{{- if or (.Has "pk") (.Has "unique")}}
 
-import "context"
+func (r *{{$.StructName}}Repository) ExistsBy{{.Name}}(ctx context.Context, {{.Param}} {{.Type}}) (exists bool, err error) {
+	return
+}
+{{- end}}
+{{- end}}`
+
+const serviceTemplate = `package {{.Package}}
 
-type %sService struct {
+import (
+	"context"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+type {{.StructName}}Service struct {
 }
 
-func New%sService() *%sService {
-	return &%sService{}
+func New{{.StructName}}Service() *{{.StructName}}Service {
+	return &{{.StructName}}Service{}
 }
 
-func (s *%sService) Foo(ctx context.Context) (bar string, err error) {
+func (s *{{.StructName}}Service) Foo(ctx context.Context) (bar string, err error) {
 	return
-}`
+}
+{{- if .Fields}}
 
-const controllerTemplate = `package controller
+func (s *{{.StructName}}Service) Create(ctx context.Context, {{params .Fields}}) error {
+	return nil
+}
+{{- end}}`
+
+const controllerTemplate = `package {{.Package}}
 
 import (
 	"github.com/gin-gonic/gin"
@@ -80,29 +186,29 @@ import (
 	"github.com/rushairer/gouno"
 )
 
-type %sController struct {
+type {{.StructName}}Controller struct {
 }
 
-func New%sController() *%sController {
-	return &%sController{}
+func New{{.StructName}}Controller() *{{.StructName}}Controller {
+	return &{{.StructName}}Controller{}
 }
 
-func (c *%sController) Foo(ctx *gin.Context) {
+func (c *{{.StructName}}Controller) Foo(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gouno.NewSuccessResponse("bar"))
 }`
 
-const taskTemplate = `package task
+const taskTemplate = `package {{.Package}}
 
 import "context"
 
-type %sTask struct {
+type {{.StructName}}Task struct {
 }
 
-func New%sTask() *%sTask {
-	return &%sTask{}
+func New{{.StructName}}Task() *{{.StructName}}Task {
+	return &{{.StructName}}Task{}
 }
 
-func (t *%sTask) Run(ctx context.Context) error {
+func (t *{{.StructName}}Task) Run(ctx context.Context) error {
 	return nil
 }`
 
@@ -140,30 +246,190 @@ func loadProjectConfig() *GounoConfig {
 	return &cfg
 }
 
-// loadTemplate 加载指定模板集中的模板
-// 搜索路径：
-// 1. ~/.gouno/templates/<templateSet>/<typeName>.tmpl
-// 2. 内置模板
+// templateFile 是模板中的一个文件
+// 单文件模板（<type>.tmpl）的 Name 为空；目录模板（<type>/）的 Name 为文件相对于目录的路径，
+// 其本身也是模板，如 {{.Snake}}_handler.go，渲染后作为生成文件相对于生成目录的路径
+type templateFile struct {
+	Name    string
+	Content string
+}
+
+// loadTemplate 加载指定模板集中的单文件模板，模板为目录时报错
 func loadTemplate(cmd *cobra.Command, templateSet, typeName string) (string, error) {
-	// 1. 用户模板目录
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		localPath := filepath.Join(homeDir, templateDirName, templatesDirName, templateSet, typeName+".tmpl")
-		if content, err := os.ReadFile(localPath); err == nil {
-			cmd.Printf("Using template: %s\n", localPath)
-			return string(content), nil
+	files, err := loadTemplateFiles(cmd, templateSet, typeName)
+	if err != nil {
+		return "", err
+	}
+	if len(files) != 1 || files[0].Name != "" {
+		return "", fmt.Errorf("%s template of set %q must be a single %s%s file", typeName, templateSet, typeName, templateExt)
+	}
+	return files[0].Content, nil
+}
+
+// loadTemplateFiles 加载指定模板集中的模板，并输出模板来自查找链的哪一层
+// 模板集带有 template.yaml 清单时，先检查 gouno 版本要求与支持的类型
+// 搜索路径（见 templateLayers），同一模板集中目录模板 <typeName>/ 优先于 <typeName>.tmpl：
+// 1. <project>/.gouno/templates/<templateSet>/<typeName>.tmpl
+// 2. .gouno.yaml 中 template-paths 列出的目录
+// 3. ~/.gouno/templates/<templateSet>/<typeName>.tmpl
+// 4. 清单中 extends 声明的父模板集，依次重复 1-3
+// 5. 内置模板（模板集为 default 或最终继承自 default 时）
+func loadTemplateFiles(cmd *cobra.Command, templateSet, typeName string) ([]templateFile, error) {
+	manifest, err := loadManifest(templateSet)
+	if err != nil {
+		return nil, err
+	}
+	if !manifest.supports(typeName) {
+		return nil, fmt.Errorf("template set %q does not support %s templates (supported: %s)",
+			templateSet, typeName, strings.Join(manifest.Types, ", "))
+	}
+
+	files, source, err := lookupTemplate(templateSet, manifest, typeName)
+	if err != nil {
+		return nil, err
+	}
+	if source != "" && !currentSession.templates[source] {
+		currentSession.templates[source] = true
+		cmd.Printf("Using %s template from %s\n", typeName, source)
+	}
+	return files, nil
+}
+
+// lookupTemplate 沿模板集的继承链查找模板，返回模板文件与来源描述
+// 直接使用 default 模板集的内置模板时来源为空
+func lookupTemplate(templateSet string, manifest *TemplateManifest, typeName string) ([]templateFile, string, error) {
+	chain := manifest.inheritance(templateSet)
+	for _, set := range chain {
+		if dir, layer, ok := locateTemplateDir(set, typeName); ok {
+			files, err := readTemplateDir(dir)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read template: %w", err)
+			}
+			return files, layer.Name + ": " + dir + string(filepath.Separator), nil
+		}
+		if path, layer, ok := locateTemplateFile(set, typeName+templateExt); ok {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read template: %w", err)
+			}
+			return []templateFile{{Content: string(content)}}, layer.Name + ": " + path, nil
+		}
+	}
+
+	base := chain[len(chain)-1]
+	if tmpl, ok := builtinTemplates[typeName]; ok && (base == "default" || base == "") {
+		files := []templateFile{{Content: tmpl}}
+		if len(chain) == 1 {
+			return files, "", nil
+		}
+		return files, layerBuiltin + ": default (inherited by " + templateSet + ")", nil
+	}
+
+	if _, found, _ := findTemplateSet(templateSet); !found && templateSet != "default" {
+		return nil, "", fmt.Errorf("template set %q not found (run: gouno gen template install <dir|archive> %s)", templateSet, templateSet)
+	}
+	if _, ok := builtinTemplates[typeName]; ok {
+		return nil, "", fmt.Errorf("template set %q has no %s template (add \"extends: default\" to its %s to inherit the builtin one)",
+			templateSet, typeName, manifestFileName)
+	}
+	return nil, "", fmt.Errorf("unknown template type: %s", typeName)
+}
+
+// readTemplateDir 读取目录模板中的所有文件（忽略隐藏文件），按路径排序
+func readTemplateDir(dir string) ([]templateFile, error) {
+	var files []templateFile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
+		if strings.HasPrefix(entry.Name(), ".") && path != dir {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, templateFile{Name: filepath.ToSlash(rel), Content: string(content)})
+		return nil
+	})
+	if err == nil && len(files) == 0 {
+		err = fmt.Errorf("template directory %s is empty", dir)
+	}
+	return files, err
+}
+
+// 模板查找链中各层的名称
+const (
+	layerProject = "project"
+	layerConfig  = "template-paths"
+	layerHome    = "home"
+	layerBuiltin = "builtin"
+)
+
+// templateLayer 是模板查找链中的一层，Dir 下的每个子目录是一个模板集
+type templateLayer struct {
+	Name string
+	Dir  string
+}
+
+// templateLayers 返回按优先级排列的模板查找链（不含内置模板）：
+// 1. <project>/.gouno/templates，可随项目一起纳入版本管理
+// 2. .gouno.yaml 中 template-paths 列出的目录，相对路径相对于项目根目录
+// 3. ~/.gouno/templates
+func templateLayers() []templateLayer {
+	var layers []templateLayer
+	if cwd, err := os.Getwd(); err == nil {
+		layers = append(layers, templateLayer{layerProject, filepath.Join(cwd, templateDirName, templatesDirName)})
+		if cfg := loadProjectConfig(); cfg != nil {
+			for _, dir := range cfg.TemplatePaths {
+				if strings.HasPrefix(dir, "~/") {
+					if home, err := os.UserHomeDir(); err == nil {
+						dir = filepath.Join(home, dir[2:])
+					}
+				}
+				if !filepath.IsAbs(dir) {
+					dir = filepath.Join(cwd, dir)
+				}
+				layers = append(layers, templateLayer{layerConfig, dir})
+			}
+		}
+	}
+	if root, err := templateSetDir(); err == nil {
+		layers = append(layers, templateLayer{layerHome, root})
 	}
+	return layers
+}
 
-	// 2. 内置模板（仅 default 模板集）
-	if tmpl, ok := builtinTemplates[typeName]; ok {
-		if templateSet == "default" || templateSet == "" {
-			return tmpl, nil
+// locateTemplateDir 沿查找链查找模板集中的目录模板，返回第一个存在的目录及其所在层
+func locateTemplateDir(templateSet, typeName string) (string, templateLayer, bool) {
+	for _, layer := range templateLayers() {
+		dir := filepath.Join(layer.Dir, templateSet, typeName)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, layer, true
 		}
-		return "", fmt.Errorf("template set %q not found (run: gouno-cli template install %s <url>)", templateSet, templateSet)
 	}
+	return "", templateLayer{}, false
+}
 
-	return "", fmt.Errorf("unknown template type: %s", typeName)
+// locateTemplateFile 沿查找链查找模板集中的文件，返回第一个存在的路径及其所在层
+func locateTemplateFile(templateSet, name string) (string, templateLayer, bool) {
+	for _, layer := range templateLayers() {
+		path := filepath.Join(layer.Dir, templateSet, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, layer, true
+		}
+	}
+	return "", templateLayer{}, false
 }
 
 // templateSetDir 返回用户模板集的根目录 ~/.gouno/templates/
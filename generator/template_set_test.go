@@ -17,8 +17,8 @@ func TestLoadTemplateBuiltin(t *testing.T) {
 	if content == "" {
 		t.Fatal("template content is empty")
 	}
-	if !contains(content, "package domain") {
-		t.Errorf("template should contain 'package domain'")
+	if !contains(content, "package {{.Package}}") {
+		t.Errorf("template should contain 'package {{.Package}}'")
 	}
 }
 
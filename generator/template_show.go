@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/rushairer/gouno"
+	"github.com/spf13/cobra"
+)
+
+var templateShowCmd = &cobra.Command{
+	Use:   "show [set] [type]",
+	Short: "Show a template set's manifest, or the content of one of its templates",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		set, found, err := findTemplateSet(name)
+		if err != nil {
+			return err
+		}
+		if !found && name != "default" {
+			return fmt.Errorf("template set %q not found", name)
+		}
+
+		if len(args) == 2 {
+			return showTemplate(cmd, name, args[1])
+		}
+
+		out := cmd.OutOrStdout()
+		if !found {
+			fmt.Fprintln(out, "Name:        default")
+			fmt.Fprintf(out, "Version:     %s\n", gouno.Version)
+			fmt.Fprintln(out, "Location:    builtin")
+			fmt.Fprintf(out, "Templates:   %s\n", strings.Join(builtinTemplateTypes(), ", "))
+			return nil
+		}
+
+		manifest, err := loadManifest(name)
+		if err != nil {
+			return err
+		}
+		if manifest == nil {
+			manifest = &TemplateManifest{}
+		}
+		types, err := templateSetTypes(set.Dir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Name:        %s\n", name)
+		printField := func(label, value string) {
+			if value != "" {
+				fmt.Fprintf(out, "%-13s%s\n", label+":", value)
+			}
+		}
+		printField("Version", manifest.Version)
+		printField("Extends", strings.Join(manifest.inheritance(name)[1:], " -> "))
+		printField("Description", manifest.Description)
+		printField("Requires", minGounoRequirement(manifest))
+		fmt.Fprintf(out, "Location:    %s (%s)\n", set.Dir, set.Layer)
+		printField("Types", strings.Join(manifest.Types, ", "))
+		fmt.Fprintf(out, "Templates:   %s\n", strings.Join(types, ", "))
+		if len(manifest.Paths) > 0 {
+			fmt.Fprintln(out, "Paths:")
+			for _, typeName := range slices.Sorted(maps.Keys(manifest.Paths)) {
+				fmt.Fprintf(out, "  %-11s %s\n", typeName, manifest.Paths[typeName])
+			}
+		}
+		if len(manifest.Kinds) > 0 {
+			fmt.Fprintln(out, "Kinds:")
+			for _, kindName := range slices.Sorted(maps.Keys(manifest.Kinds)) {
+				kind := manifest.Kinds[kindName]
+				description := cmp.Or(kind.Path, defaultKindPath(kindName))
+				if len(kind.Aliases) > 0 {
+					description += " (aliases: " + strings.Join(kind.Aliases, ", ") + ")"
+				}
+				fmt.Fprintf(out, "  %-11s %s\n", kindName, description)
+			}
+		}
+		if len(manifest.Variables) > 0 {
+			fmt.Fprintln(out, "Variables:")
+			for _, v := range manifest.Variables {
+				fmt.Fprintf(out, "  %-11s %s\n", v.Name, describeVariable(v))
+			}
+		}
+		if len(manifest.Hooks) > 0 {
+			fmt.Fprintln(out, "Hooks:")
+			for _, hook := range manifest.Hooks {
+				fmt.Fprintf(out, "  %s\n", hook)
+			}
+		}
+		return nil
+	},
+}
+
+// showTemplate 输出模板集中指定类型的模板内容，查找顺序与 loadTemplate 相同
+// 目录模板逐个输出其中的文件，每个文件前标注文件名
+func showTemplate(cmd *cobra.Command, name, typeName string) error {
+	manifest, err := loadManifest(name)
+	if err != nil {
+		return err
+	}
+	files, _, err := lookupTemplate(name, manifest, typeName)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	for i, file := range files {
+		if file.Name != "" {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintf(out, "==> %s <==\n", file.Name)
+		}
+		fmt.Fprint(out, file.Content)
+		if !strings.HasSuffix(file.Content, "\n") {
+			fmt.Fprintln(out)
+		}
+	}
+	return nil
+}
+
+// minGounoRequirement 返回清单中的 gouno 版本要求描述
+func minGounoRequirement(manifest *TemplateManifest) string {
+	if manifest.MinGounoVersion == "" {
+		return ""
+	}
+	return "gouno >= " + manifest.MinGounoVersion
+}
+
+// describeVariable 返回变量的提示与约束描述
+func describeVariable(v TemplateVariable) string {
+	var parts []string
+	if v.Prompt != "" {
+		parts = append(parts, v.Prompt)
+	}
+	if v.Default != "" {
+		parts = append(parts, "default "+v.Default)
+	}
+	if v.Required {
+		parts = append(parts, "required")
+	}
+	if len(v.Choices) > 0 {
+		parts = append(parts, "one of "+strings.Join(v.Choices, "/"))
+	}
+	if v.Pattern != "" {
+		parts = append(parts, "matching "+v.Pattern)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,391 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// withTest 判断是否需要生成测试文件
+// 优先级：--with-test flag > .gouno.yaml 中的 with-test
+func withTest(cmd *cobra.Command) bool {
+	if flag := cmd.Flag("with-test"); flag != nil && flag.Changed {
+		v, _ := cmd.Flags().GetBool("with-test")
+		return v
+	}
+	if cfg := loadProjectConfig(); cfg != nil {
+		return cfg.WithTest
+	}
+	return false
+}
+
+// generateTestFile 使用 <templateName>_test 模板为已生成的文件生成同目录下的 <name>_test.go
+func generateTestFile(cmd *cobra.Command, file *generatedFile, templateSet, templateName string) (*generatedFile, error) {
+	testTemplateName := templateName + "_test"
+	tmpl, err := loadTemplate(cmd, templateSet, testTemplateName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := renderTemplate(testTemplateName, tmpl, file.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	typeName := file.TypeName + " test"
+	testPath := strings.TrimSuffix(file.Path, ".go") + "_test.go"
+	formatted, err := formatGoSource(testPath, []byte(content), file.Data.Module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s file: %w", typeName, err)
+	}
+
+	test := &generatedFile{
+		TypeName:   typeName,
+		Path:       testPath,
+		ImportPath: file.ImportPath,
+		Data:       file.Data,
+		Content:    string(formatted),
+	}
+	if err := checkDeclarations(test, nil); err != nil {
+		return nil, err
+	}
+	if test.Action, err = writeFile(cmd, typeName, test.Path, test.Content); err != nil {
+		return nil, err
+	}
+	if err := recordLock(cmd, test, testTemplateName, "", tmpl, filepath.Dir(file.Path)); err != nil {
+		return nil, err
+	}
+	return test, nil
+}
+
+const domainTestTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNew{{.StructName}}(t *testing.T) {
+	tests := []struct {
+		name string
+		want {{.StructName}}
+	}{
+		{name: "zero value", want: {{.StructName}}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New{{.StructName}}({{range $i, $f := .Fields}}{{if $i}}, {{end}}tt.want.{{$f.Name}}{{end}})
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("New{{.StructName}}() = %+v; want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func Test{{.StructName}}Foo(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&{{.StructName}}{}).Foo(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Foo() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Foo() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+`
+
+const repositoryTestTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"testing"
+)
+
+func Test{{.StructName}}RepositoryFoo(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New{{.StructName}}Repository().Foo(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Foo() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Foo() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+`
+
+const serviceTestTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"testing"
+)
+
+func Test{{.StructName}}ServiceFoo(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New{{.StructName}}Service().Foo(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Foo() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Foo() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+`
+
+const controllerTestTemplate = `package {{.Package}}
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rushairer/gouno"
+)
+
+func Test{{.StructName}}ControllerFoo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		wantCode int
+		wantData any
+	}{
+		{name: "success", wantCode: http.StatusOK, wantData: "bar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/foo", New{{.StructName}}Controller().Foo)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/foo", nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d; want %d", w.Code, tt.wantCode)
+			}
+			var resp gouno.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response envelope: %v", err)
+			}
+			if resp.Code != tt.wantCode || resp.Data != tt.wantData {
+				t.Errorf("response = %+v; want code %d data %v", resp, tt.wantCode, tt.wantData)
+			}
+		})
+	}
+}
+`
+
+const taskTestTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"testing"
+)
+
+func Test{{.StructName}}TaskRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "cancelled context"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			if err := New{{.StructName}}Task().Run(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v; wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+`
+
+const crudRepositoryTestTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	{{.Import "domain"}}
+)
+
+func TestMemory{{.StructName}}Repository(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemory{{.StructName}}Repository()
+
+	item := &domain.{{.StructName}}{ {{- if not .ID.IsInteger}}ID: "1"{{end}}}
+	if err := r.Create(ctx, item); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      {{.ID.Type}}
+		wantErr error
+	}{
+		{name: "existing", id: item.ID},
+		{name: "missing", id: {{if .ID.IsInteger}}item.ID + 1{{else}}"missing"{{end}}, wantErr: Err{{.StructName}}NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.Get(ctx, tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Get() error = %v; want %v", err, tt.wantErr)
+			}
+			if err := r.Update(ctx, &domain.{{.StructName}}{ID: tt.id}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Update() error = %v; want %v", err, tt.wantErr)
+			}
+			if err := r.Delete(ctx, tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Delete() error = %v; want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	items, err := r.List(ctx)
+	if err != nil || len(items) != 0 {
+		t.Errorf("List() = %v, %v; want empty", items, err)
+	}
+}
+`
+
+const crudServiceTestTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	{{.Import "domain"}}
+	{{.Import "repository"}}
+)
+
+func Test{{.StructName}}Service(t *testing.T) {
+	ctx := context.Background()
+	s := New{{.StructName}}Service(repository.NewMemory{{.StructName}}Repository())
+
+	item := &domain.{{.StructName}}{ {{- if not .ID.IsInteger}}ID: "1"{{end}}}
+	if err := s.Create(ctx, item); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      {{.ID.Type}}
+		wantErr error
+	}{
+		{name: "existing", id: item.ID},
+		{name: "missing", id: {{if .ID.IsInteger}}item.ID + 1{{else}}"missing"{{end}}, wantErr: Err{{.StructName}}NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Get(ctx, tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Get() error = %v; want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+`
+
+const crudControllerTestTemplate = `package {{.Package}}
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rushairer/gouno"
+
+	{{.Import "repository"}}
+	{{.Import "service"}}
+)
+
+func Test{{.StructName}}Controller(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c := New{{.StructName}}Controller(service.New{{.StructName}}Service(repository.NewMemory{{.StructName}}Repository()))
+	router := gin.New()
+	router.GET("/{{.Snake}}", c.List)
+	router.GET("/{{.Snake}}/:id", c.Get)
+	router.POST("/{{.Snake}}", c.Create)
+	router.DELETE("/{{.Snake}}/:id", c.Delete)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantCode int
+	}{
+		{name: "list", method: http.MethodGet, path: "/{{.Snake}}", wantCode: http.StatusOK},
+		{name: "get missing", method: http.MethodGet, path: "/{{.Snake}}/404", wantCode: http.StatusNotFound},
+		{{- if .ID.IsInteger}}
+		{name: "get invalid id", method: http.MethodGet, path: "/{{.Snake}}/abc", wantCode: http.StatusBadRequest},
+		{{- end}}
+		{name: "create invalid body", method: http.MethodPost, path: "/{{.Snake}}", wantCode: http.StatusBadRequest},
+		{name: "delete missing", method: http.MethodDelete, path: "/{{.Snake}}/404", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d; want %d", w.Code, tt.wantCode)
+			}
+			var resp gouno.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response envelope: %v", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("response code = %d; want %d", resp.Code, tt.wantCode)
+			}
+		})
+	}
+}
+`
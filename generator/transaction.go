@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// transaction 记录一次多步生成过程中对文件系统的修改，某一步失败时用于回滚已完成的步骤
+// 所有方法对 nil 接收者均为空操作，未开启事务时无需判断
+type transaction struct {
+	files []fileBackup // 按首次修改的顺序记录
+	dirs  []string     // 新建的目录，按创建顺序（父目录在前）
+	seen  map[string]bool
+}
+
+// fileBackup 是文件在被修改前的状态
+type fileBackup struct {
+	path    string
+	content []byte
+	existed bool
+}
+
+// inTransaction 在事务中执行 fn，fn 返回错误时回滚其间写入的文件（--dry-run 下不会写入，无需回滚）
+func inTransaction(cmd *cobra.Command, fn func() error) error {
+	if currentSession.tx != nil {
+		return fn()
+	}
+	tx := &transaction{seen: make(map[string]bool)}
+	currentSession.tx = tx
+	defer func() { currentSession.tx = nil }()
+
+	err := fn()
+	if err == nil {
+		return nil
+	}
+	restored, rollbackErr := tx.rollback()
+	cacheDir := string(filepath.Separator) + filepath.Join(templateDirName, cacheDirName) + string(filepath.Separator)
+	for _, path := range restored {
+		// 合并基准缓存与锁文件随生成文件一起还原，不单独输出
+		if !strings.Contains(path, cacheDir) && filepath.Base(path) != lockFileName {
+			cmd.Printf("Rolled back: %s\n", path)
+		}
+	}
+	if rollbackErr != nil {
+		return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+	}
+	return err
+}
+
+// recordFile 在文件被写入前记录其原始内容，同一文件只记录第一次
+func (tx *transaction) recordFile(path string) {
+	if tx == nil || tx.seen[path] {
+		return
+	}
+	tx.seen[path] = true
+	content, err := os.ReadFile(path)
+	tx.files = append(tx.files, fileBackup{path: path, content: content, existed: err == nil})
+}
+
+// recordDir 在创建目录前记录其中尚不存在的各级目录
+func (tx *transaction) recordDir(dir string) {
+	if tx == nil {
+		return
+	}
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil || d == filepath.Dir(d) {
+			break
+		}
+		missing = append(missing, d)
+	}
+	slices.Reverse(missing)
+	tx.dirs = append(tx.dirs, missing...)
+}
+
+// rollback 按相反顺序还原被修改或删除的文件、删除新建的文件与（已为空的）新建目录，返回还原的文件路径
+func (tx *transaction) rollback() ([]string, error) {
+	if tx == nil {
+		return nil, nil
+	}
+	var restored []string
+	var errs []error
+	for _, backup := range slices.Backward(tx.files) {
+		var err error
+		if backup.existed {
+			// 删除的文件所在的目录可能已因变空而被删除
+			if err = os.MkdirAll(filepath.Dir(backup.path), 0755); err == nil {
+				err = os.WriteFile(backup.path, backup.content, 0644)
+			}
+		} else {
+			err = os.Remove(backup.path)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		restored = append(restored, backup.path)
+	}
+	for _, dir := range slices.Backward(tx.dirs) {
+		os.Remove(dir)
+	}
+	return restored, errors.Join(errs...)
+}
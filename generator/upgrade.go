@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [file...]",
+	Short: "Re-render generated files whose template changed",
+	Long: `Re-render the files recorded in .gouno.lock that were generated from the
+active template set (--template-set > .gouno.yaml > "default") with a template
+that has changed since, e.g. after installing a new version of the set.
+
+Files that were not edited after generation are updated in place. Edited files
+are reported and kept unless --merge (three-way merge of your edits with the
+new output, leaving conflict markers where both changed the same lines) or
+--force (discard the edits) is given. Template set variables keep the values
+recorded when the file was generated unless --var sets them. Arguments limit
+the upgrade to the given files or directories.`,
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		statuses, err := generatedFileStatuses()
+		if err != nil {
+			return err
+		}
+		templateSet := resolveTemplateSet(cmd)
+		var outdated []*generatedFileStatus
+		for _, status := range statuses {
+			if status.Outdated && !status.Missing && status.Entry.TemplateSet == templateSet && upgradeSelected(status.Key, args) {
+				outdated = append(outdated, status)
+			}
+		}
+		if len(outdated) == 0 {
+			cmd.Printf("All files generated from template set %q are up to date\n", templateSet)
+			return nil
+		}
+
+		counts := make(map[string]int)
+		err = inTransaction(cmd, func() error {
+			for _, status := range outdated {
+				action, err := upgradeFile(cmd, status)
+				if err != nil {
+					return fmt.Errorf("%s: %w", status.Key, err)
+				}
+				counts[action]++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		var summary []string
+		for _, action := range []string{actionOverwritten, actionMerged, actionUnchanged, actionSkipped, actionNoArgs} {
+			if counts[action] > 0 {
+				summary = append(summary, fmt.Sprintf("%d %s", counts[action], upgradeActionNames[action]))
+			}
+		}
+		verb := "Upgraded"
+		if isDryRun(cmd) {
+			verb = "Would upgrade"
+		}
+		cmd.Printf("%s %d outdated file(s): %s\n", verb, len(outdated), strings.Join(summary, ", "))
+		return nil
+	},
+}
+
+const (
+	// actionUnchanged 表示重新渲染的结果与现有文件相同
+	actionUnchanged = "unchanged"
+	// actionNoArgs 表示文件没有记录生成参数（如 mock 与 from-openapi 的输出），无法重新渲染
+	actionNoArgs = "no-args"
+)
+
+// upgradeActionNames 是升级摘要中各操作的名称
+var upgradeActionNames = map[string]string{
+	actionOverwritten: "updated",
+	actionUnchanged:   "unchanged",
+	actionMerged:      "merged",
+	actionSkipped:     "kept (modified)",
+	actionNoArgs:      "kept (no recorded arguments)",
+}
+
+// upgradeSelected 判断文件是否在参数指定的文件或目录中，没有参数时选择所有文件
+func upgradeSelected(key string, args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(args, func(arg string) bool {
+		arg = path.Clean(filepath.ToSlash(arg))
+		return key == arg || strings.HasPrefix(key, arg+"/")
+	})
+}
+
+// upgradeFile 使用当前模板重新渲染一个过时的生成文件：
+// 未修改的文件直接更新，修改过的文件在指定 --merge 时三方合并、指定 --force 时覆盖，否则保留并提示
+func upgradeFile(cmd *cobra.Command, status *generatedFileStatus) (string, error) {
+	entry := status.Entry
+	if len(entry.Args) == 0 {
+		cmd.Printf("Kept %s file: %s (generated without recorded arguments, re-run the command that generated it)\n", entry.Type, status.Path)
+		return actionNoArgs, nil
+	}
+
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	// 按记录的目录重新构造模板数据，嵌套名称的分组子目录由 newTemplateData 重新添加
+	dir := entry.Dir
+	if dir == "" {
+		dir = path.Dir(status.Key)
+	}
+	group, _ := splitName(entry.Args[0])
+	currentSession.paths[entry.Type] = filepath.FromSlash(strings.TrimSuffix(dir, "/"+group))
+	defer delete(currentSession.paths, entry.Type)
+	data, _, err := newTemplateData(cmd, entry.Args, entry.Type, dir)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := loadManifest(data.TemplateSet)
+	if err != nil {
+		return "", err
+	}
+	if data.Vars, err = upgradeVariables(cmd, entry, manifest); err != nil {
+		return "", err
+	}
+	templateName := entry.Template
+	if entry.TemplateFile != "" {
+		templateName += "/" + entry.TemplateFile
+	}
+	file, err := renderTemplateFile(cmd, entry.Type, templateName, status.Template, status.Path, data)
+	if err != nil {
+		return "", err
+	}
+
+	// 模板的变化不影响输出时只更新记录
+	if current, err := os.ReadFile(status.Path); err == nil && !status.Modified && string(current) == file.Content {
+		file.Action = actionUnchanged
+		return actionUnchanged, recordLock(cmd, file, entry.Template, entry.TemplateFile, status.Template, filepath.Join(projectRoot, filepath.FromSlash(dir)))
+	}
+
+	action := actionOverwritten
+	if status.Modified {
+		merge, _ := cmd.Flags().GetBool("merge")
+		force, _ := cmd.Flags().GetBool("force")
+		switch {
+		case merge:
+			action = actionMerged
+		case !force:
+			cmd.Printf("Kept modified %s file: %s (use --merge to merge the new template output or --force to overwrite)\n", entry.Type, status.Path)
+			return actionSkipped, nil
+		}
+	}
+	if file.Action, err = writeFileAs(cmd, entry.Type, status.Path, file.Content, action); err != nil {
+		return "", err
+	}
+	if err := recordLock(cmd, file, entry.Template, entry.TemplateFile, status.Template, filepath.Join(projectRoot, filepath.FromSlash(dir))); err != nil {
+		return "", err
+	}
+	return file.Action, nil
+}
+
+// upgradeVariables 返回重新渲染文件所用的模板集变量：优先使用 --var 指定的值，其次是生成时记录的取值，
+// 模板集新增的变量与没有记录变量的旧文件按生成时的方式解析
+func upgradeVariables(cmd *cobra.Command, entry *lockEntry, manifest *TemplateManifest) (map[string]string, error) {
+	if entry.Vars == nil {
+		return templateVariables(cmd, entry.TemplateSet, manifest)
+	}
+	vars := maps.Clone(entry.Vars)
+	if manifest != nil && slices.ContainsFunc(manifest.Variables, func(v TemplateVariable) bool {
+		_, ok := vars[v.Name]
+		return !ok
+	}) {
+		defaults, err := templateVariables(cmd, entry.TemplateSet, manifest)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range defaults {
+			if _, ok := vars[name]; !ok {
+				vars[name] = value
+			}
+		}
+	}
+	return resolveVariables(cmd, manifest, vars)
+}
+
+func init() {
+	upgradeCmd.Flags().String("template-set", "", "template set to upgrade files of")
+	upgradeCmd.Flags().Bool("merge", false, "three-way merge the new output into modified files")
+	upgradeCmd.Flags().BoolP("force", "f", false, "overwrite modified files, discarding their edits")
+	upgradeCmd.Flags().Bool("dry-run", false, "report the files that would be upgraded without writing them")
+	upgradeCmd.Flags().Bool("diff", false, "show a unified diff of each upgrade without writing (implies --dry-run)")
+	upgradeCmd.Flags().StringArray("var", nil, "set a template set variable as name=value, overriding the value recorded at generation (repeatable)")
+	upgradeCmd.Flags().Bool("no-hooks", false, "skip the post-generation hooks declared in .gouno.yaml and the template set")
+	upgradeCmd.Flags().Bool("trust-hooks", false, "run the template set's hooks even if they are not listed in trusted-hooks in .gouno.yaml")
+}
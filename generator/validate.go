@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// validateName 检查名称能否生成可编译的代码，不能时返回带修改建议的错误：
+// 1. 以 / 分隔的每一段只能包含字母、数字与下划线，且以字母开头（以 _ 开头的文件会被 go build 忽略）
+// 2. 分组会成为包名，不能是 Go 关键字或预声明标识符
+// 3. 驼峰命名首字母小写后会作为模板中的变量名，不能是 Go 关键字或预声明标识符
+// 4. 文件名不能以 _test 或 _<GOOS>、_<GOARCH> 结尾，否则会被当作测试文件或受构建约束限制
+func validateName(name, typeName string) error {
+	segments := strings.Split(strings.Trim(filepath.ToSlash(name), "/"), "/")
+	for _, segment := range segments {
+		if !isNameSegment(segment) {
+			msg := fmt.Sprintf("invalid name %q: %q must start with a letter and contain only letters, digits and underscores", name, segment)
+			if suggestion := suggestSegment(segment); suggestion != "" {
+				msg += fmt.Sprintf(" (try %q)", strings.Replace(name, segment, suggestion, 1))
+			}
+			return errors.New(msg)
+		}
+	}
+
+	group, base := splitName(name)
+	if group != "" {
+		for _, segment := range strings.Split(group, "/") {
+			if reason := reservedReason(strings.ToLower(segment)); reason != "" {
+				return fmt.Errorf("invalid name %q: group %q would be the package name, but %s is %s (try %q)",
+					name, segment, strings.ToLower(segment), reason, strings.Replace(name, segment, segment+"s", 1))
+			}
+		}
+	}
+
+	ident := lowerFirst(structName(name))
+	if reason := reservedReason(ident); reason != "" {
+		return fmt.Errorf("invalid name %q: templates use %s as an identifier, but it is %s (try a more specific name such as %q)",
+			name, ident, reason, joinName(group, base+"_"+typeName))
+	}
+
+	if suffix, reason := fileSuffix(base); suffix != "" {
+		return fmt.Errorf("invalid name %q: %s.go would %s (try %q)",
+			name, base, reason, joinName(group, suffix+"_"+strings.TrimSuffix(base, "_"+suffix)))
+	}
+	return nil
+}
+
+// isNameSegment 判断名称的一段是否以字母开头且只包含字母、数字与下划线
+func isNameSegment(segment string) bool {
+	for i, r := range segment {
+		if !unicode.IsLetter(r) && (i == 0 || (r != '_' && !unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return segment != ""
+}
+
+// suggestSegment 将名称的一段改写为合法的形式：非法字符替换为下划线，开头的数字移到末尾，如 123-foo 改写为 foo_123
+func suggestSegment(segment string) string {
+	parts := strings.FieldsFunc(segment, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	i := slices.IndexFunc(parts, func(part string) bool { return unicode.IsLetter([]rune(part)[0]) })
+	if i < 0 {
+		return ""
+	}
+	suggestion := strings.Join(append(parts[i:], parts[:i]...), "_")
+	if suggestion == segment {
+		return ""
+	}
+	return suggestion
+}
+
+// reservedReason 返回标识符不可用的原因（Go 关键字或预声明标识符），可用时返回空字符串
+func reservedReason(ident string) string {
+	switch {
+	case token.IsKeyword(ident):
+		return "a Go keyword"
+	case types.Universe.Lookup(ident) != nil:
+		return "a predeclared Go identifier"
+	}
+	return ""
+}
+
+// fileSuffix 返回文件名中使其成为测试文件或受构建约束限制的后缀及原因
+func fileSuffix(base string) (string, string) {
+	i := strings.LastIndex(base, "_")
+	if i <= 0 {
+		return "", ""
+	}
+	suffix := strings.ToLower(base[i+1:])
+	switch {
+	case suffix == "test":
+		return base[i+1:], "be treated as a test file"
+	case knownOS[suffix]:
+		return base[i+1:], "only be built on GOOS=" + suffix
+	case knownArch[suffix]:
+		return base[i+1:], "only be built on GOARCH=" + suffix
+	}
+	return "", ""
+}
+
+func joinName(group, base string) string {
+	if group == "" {
+		return base
+	}
+	return group + "/" + base
+}
+
+// knownOS 与 knownArch 是 go build 按文件名后缀识别的 GOOS 与 GOARCH
+var knownOS = setOf("aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js",
+	"linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos")
+
+var knownArch = setOf("386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64", "mips",
+	"mipsle", "mips64", "mips64le", "mips64p32", "mips64p32le", "ppc", "ppc64", "ppc64le", "riscv", "riscv64",
+	"s390", "s390x", "sparc", "sparc64", "wasm")
+
+func setOf(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// maxDeclarationConflicts 是声明冲突时最多列出的声明数量
+const maxDeclarationConflicts = 5
+
+// checkDeclarations 检查生成的 Go 文件的顶层声明是否与目标包中已有文件的声明冲突
+// skip 是本次生成将要写入的文件，不参与比较；测试文件同时与包中的其他测试文件比较
+func checkDeclarations(file *generatedFile, skip []string) error {
+	if !strings.HasSuffix(file.Path, ".go") {
+		return nil
+	}
+	fset := token.NewFileSet()
+	generated, err := parser.ParseFile(fset, file.Path, file.Content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil // 生成的内容已经过格式化检查，这里只可能是旧式模板
+	}
+	declared := topLevelNames(generated)
+
+	dir := filepath.Dir(file.Path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil // 目录尚不存在
+	}
+	isTest := strings.HasSuffix(file.Path, "_test.go")
+	projectRoot, _ := os.Getwd()
+	var conflicts []string
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || slices.Contains(skip, path) || path == file.Path ||
+			(strings.HasSuffix(name, "_test.go") && !isTest) {
+			continue
+		}
+		// 受构建约束限制、不在当前平台编译的文件不会与生成的文件同时编译
+		if ok, err := build.Default.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		existing, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil || existing.Name.Name != generated.Name.Name {
+			continue
+		}
+		for ident, pos := range topLevelNames(existing) {
+			if _, ok := declared[ident]; ok {
+				position := fset.Position(pos)
+				if rel, err := filepath.Rel(projectRoot, position.Filename); err == nil {
+					position.Filename = rel
+				}
+				conflicts = append(conflicts, fmt.Sprintf("%s (%s:%d)", ident, filepath.ToSlash(position.Filename), position.Line))
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	slices.Sort(conflicts)
+	if len(conflicts) > maxDeclarationConflicts {
+		conflicts = append(conflicts[:maxDeclarationConflicts], fmt.Sprintf("... and %d more", len(conflicts)-maxDeclarationConflicts))
+	}
+	return fmt.Errorf("%s would redeclare names already declared in package %s:\n\t%s\nchoose another name (e.g. %q) or output path (--path)",
+		file.Path, generated.Name.Name, strings.Join(conflicts, "\n\t"), file.Data.Name+"_v2")
+}
+
+// topLevelNames 返回文件中的顶层声明及其位置，方法以 <接收者类型>.<方法名> 表示，init 与 _ 不计入
+func topLevelNames(file *ast.File) map[string]token.Pos {
+	names := make(map[string]token.Pos)
+	add := func(ident *ast.Ident) {
+		if ident.Name != "_" && ident.Name != "init" {
+			names[ident.Name] = ident.Pos()
+		}
+	}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				add(decl.Name)
+			} else if recv := receiverName(decl.Recv.List[0].Type); recv != "" {
+				names[recv+"."+decl.Name.Name] = decl.Name.Pos()
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					add(spec.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						add(name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr string // 为空表示名称合法
+	}{
+		{"user", ""},
+		{"order_item", ""},
+		{"admin/user", ""},
+		{"admin/v1/user", ""},
+		{"type", `try a more specific name such as "type_domain"`},
+		{"error", "predeclared Go identifier"},
+		{"123-foo", `try "foo_123"`},
+		{"order-item", `try "order_item"`},
+		{"_hidden", `try "hidden"`},
+		{"../escape", `".." must start with a letter`},
+		{"func/user", `try "funcs/user"`},
+		{"admin//user", `"" must start with a letter`},
+		{"user_test", "would be treated as a test file"},
+		{"sync_windows", `try "windows_sync"`},
+		{"build_amd64", "GOARCH=amd64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateName(tt.name, "domain")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateName(%q) = %v; want nil", tt.name, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateName(%q) = %v; want error containing %q", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package gouno
+
+// Version is the version of this module. Template set manifests declaring
+// min-gouno-version are checked against it.
+const Version = "1.0.0"